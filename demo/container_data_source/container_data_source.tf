@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    docker = {
+      source = "adduc/docker"
+    }
+  }
+}
+
+provider "docker" {
+}
+
+variable "container" {
+  description = "The container to inspect"
+  type        = string
+}
+
+data "docker_container" "container" {
+  container = var.container
+}
+
+output "container" {
+  value = {
+    id    = data.docker_container.container.id
+    name  = data.docker_container.container.name
+    image = data.docker_container.container.image
+  }
+}
+
+output "env_names" {
+  value = nonsensitive(keys(data.docker_container.container.env_map))
+}
@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    docker = {
+      source = "adduc/docker"
+    }
+  }
+}
+
+provider "docker" {
+}
+
+variable "image" {
+  description = "The image to run (must already be pulled)"
+  type        = string
+  default     = "nginx:alpine"
+}
+
+resource "docker_container" "web" {
+  name  = "terraform-provider-docker-demo"
+  image = var.image
+
+  healthcheck = {
+    test     = ["CMD", "wget", "-q", "-O", "/dev/null", "http://localhost"]
+    interval = 2
+  }
+
+  wait_for     = "healthy"
+  wait_timeout = 30
+}
+
+output "container" {
+  value = docker_container.web
+}
@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    docker = {
+      source = "adduc/docker"
+    }
+  }
+}
+
+provider "docker" {
+}
+
+data "docker_events" "events" {
+  since = "1h"
+  until = "0s"
+}
+
+output "events" {
+  value = data.docker_events.events
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    docker = {
+      source = "adduc/docker"
+    }
+  }
+}
+
+provider "docker" {
+}
+
+resource "docker_image_build" "demo" {
+  context = "${path.module}/context"
+  tags    = ["terraform-provider-docker-demo:latest"]
+
+  build_args = {
+    GREETING = "hello from terraform"
+  }
+
+  remove_on_destroy = true
+}
+
+resource "docker_container" "demo" {
+  name  = "terraform-provider-docker-build-demo"
+  image = docker_image_build.demo.id
+
+  capture_logs = true
+}
+
+output "image_id" {
+  value = docker_image_build.demo.id
+}
+
+output "logs" {
+  value = docker_container.demo.logs
+}
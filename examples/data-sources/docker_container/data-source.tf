@@ -0,0 +1,8 @@
+data "docker_container" "example" {
+  container = "web"
+}
+
+output "database_url" {
+  value     = data.docker_container.example.env_map["DATABASE_URL"]
+  sensitive = true
+}
@@ -0,0 +1,8 @@
+data "docker_container_exists" "example" {
+  container = "web"
+}
+
+data "docker_logs" "example" {
+  count     = data.docker_container_exists.example.exists ? 1 : 0
+  container = data.docker_container_exists.example.id
+}
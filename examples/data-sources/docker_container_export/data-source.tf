@@ -0,0 +1,4 @@
+data "docker_container_export" "example" {
+  container   = "web"
+  output_path = "${path.module}/web.tar"
+}
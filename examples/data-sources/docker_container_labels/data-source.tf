@@ -0,0 +1,9 @@
+data "docker_container_labels" "example" {
+  container    = "web"
+  label_prefix = "com.docker.compose."
+  strip_prefix = true
+}
+
+output "compose_project" {
+  value = data.docker_container_labels.example.labels["project"]
+}
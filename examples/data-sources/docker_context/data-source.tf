@@ -0,0 +1,5 @@
+data "docker_context" "example" {}
+
+output "current_context_host" {
+  value = one([for c in data.docker_context.example.contexts : c.host if c.current])
+}
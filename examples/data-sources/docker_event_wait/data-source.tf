@@ -0,0 +1,7 @@
+data "docker_event_wait" "healthy" {
+  type      = "container"
+  action    = "health_status: healthy"
+  container = "web"
+  since     = "5m"
+  timeout   = 120
+}
@@ -0,0 +1,5 @@
+data "docker_events" "example" {
+  since      = "10m"
+  until      = "0s"
+  max_events = 50
+}
@@ -2,3 +2,21 @@ data "docker_files" "example" {
   container = "alpine"
   path      = "/etc/apk"
 }
+
+data "docker_files" "batch" {
+  container   = "alpine"
+  paths       = ["/etc/hostname", "/etc/hosts", "/etc/resolv.conf"]
+  concurrency = 2
+}
+
+data "docker_files" "checksums" {
+  container    = "alpine"
+  path         = "/usr/lib"
+  content_mode = "checksum"
+}
+
+data "docker_files" "export" {
+  container  = "alpine"
+  path       = "/usr/share"
+  output_dir = "${path.module}/export"
+}
@@ -0,0 +1,8 @@
+data "docker_image_digest" "example" {
+  name = "nginx:alpine"
+}
+
+resource "docker_container" "example" {
+  name  = "web"
+  image = data.docker_image_digest.example.repo_digest
+}
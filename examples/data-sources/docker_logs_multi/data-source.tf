@@ -0,0 +1,3 @@
+data "docker_logs_multi" "example" {
+  containers = ["web-1", "web-2"]
+}
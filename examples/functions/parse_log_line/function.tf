@@ -0,0 +1,4 @@
+output "log_line" {
+  # a stdout frame containing "hello\n"
+  value = provider::docker::parse_log_line("AQAAAAAAAAZoZWxsbwo=")
+}
@@ -0,0 +1,12 @@
+variable "config_path" {
+  type = string
+
+  validation {
+    condition     = can(provider::docker::sanitize_path(var.config_path))
+    error_message = "The config path must not traverse outside the container root."
+  }
+}
+
+output "sanitized_path" {
+  value = provider::docker::sanitize_path("/etc/./app/config.yaml") # "etc/app/config.yaml"
+}
@@ -0,0 +1,8 @@
+variable "container_name" {
+  type = string
+
+  validation {
+    condition     = can(provider::docker::validate_container_name(var.container_name))
+    error_message = "The container name must start with a letter or number and contain only letters, numbers, underscores, periods and dashes."
+  }
+}
@@ -0,0 +1,2 @@
+# Containers can be imported by ID or name
+terraform import docker_container.example web
@@ -0,0 +1,12 @@
+resource "docker_container" "example" {
+  name  = "web"
+  image = "nginx:alpine"
+
+  healthcheck = {
+    test     = ["CMD", "wget", "-q", "-O", "/dev/null", "http://localhost"]
+    interval = 2
+  }
+
+  wait_for     = "healthy"
+  wait_timeout = 30
+}
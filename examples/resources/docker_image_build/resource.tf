@@ -0,0 +1,14 @@
+resource "docker_image_build" "example" {
+  context = "${path.module}/app"
+  tags    = ["app:latest"]
+
+  build_args = {
+    VERSION = "1.2.3"
+  }
+
+  labels = {
+    "org.opencontainers.image.source" = "https://example.com/app"
+  }
+
+  remove_on_destroy = true
+}
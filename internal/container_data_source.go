@@ -0,0 +1,544 @@
+package internal
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// containerMountAttrTypes are the attribute types of a container mount
+var containerMountAttrTypes = map[string]attr.Type{
+	"type":        types.StringType,
+	"source":      types.StringType,
+	"destination": types.StringType,
+	"mode":        types.StringType,
+	"rw":          types.BoolType,
+	"propagation": types.StringType,
+}
+
+// containerPortAttrTypes are the attribute types of a container port mapping
+var containerPortAttrTypes = map[string]attr.Type{
+	"ip":           types.StringType,
+	"private_port": types.Int32Type,
+	"public_port":  types.Int32Type,
+	"type":         types.StringType,
+}
+
+// containerNetworkAttrTypes are the attribute types of a container's settings on one network
+var containerNetworkAttrTypes = map[string]attr.Type{
+	"ip_address":  types.StringType,
+	"gateway":     types.StringType,
+	"mac_address": types.StringType,
+	"aliases":     types.ListType{ElemType: types.StringType},
+}
+
+// containerHealthLogAttrTypes are the attribute types of a healthcheck result
+var containerHealthLogAttrTypes = map[string]attr.Type{
+	"start":     types.StringType,
+	"end":       types.StringType,
+	"exit_code": types.Int64Type,
+	"output":    types.StringType,
+}
+
+// containerHealthAttrTypes are the attribute types of a container's health
+var containerHealthAttrTypes = map[string]attr.Type{
+	"status":         types.StringType,
+	"failing_streak": types.Int64Type,
+	"log":            types.ListType{ElemType: types.ObjectType{AttrTypes: containerHealthLogAttrTypes}},
+}
+
+type ContainerDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+}
+
+type ContainerDataSourceModel struct {
+	Container types.String `tfsdk:"container"`
+	Host      types.String `tfsdk:"host"`
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Image     types.String `tfsdk:"image"`
+	Env       types.List   `tfsdk:"env"`
+	EnvMap    types.Map    `tfsdk:"env_map"`
+	Mounts    types.List   `tfsdk:"mounts"`
+	Ports     types.List   `tfsdk:"ports"`
+
+	NetworkSettings types.Map `tfsdk:"network_settings"`
+
+	Health types.Object `tfsdk:"health"`
+
+	RawJSON types.String `tfsdk:"raw_json"`
+}
+
+func NewContainerDataSource() datasource.DataSource {
+	return &ContainerDataSource{}
+}
+
+func (d *ContainerDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container"
+}
+
+func (d *ContainerDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Retrieve the configuration of a docker container, as reported by
+			docker inspect.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"container": schema.StringAttribute{
+				Required:    true,
+				Description: "The name or ID of the container",
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			// Computed
+
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The container ID",
+			},
+
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The container name",
+			},
+
+			"image": schema.StringAttribute{
+				Computed:    true,
+				Description: "The image the container was created from",
+			},
+
+			"env": schema.ListAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The container's environment variables, in KEY=VALUE form",
+				ElementType: types.StringType,
+			},
+
+			"env_map": schema.MapAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The container's environment variables keyed by name; variables set without a value map to an empty string",
+				ElementType: types.StringType,
+			},
+
+			"mounts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The volumes, bind mounts and tmpfs mounts of the container",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The mount type (bind, volume, tmpfs, ...)",
+						},
+						"source": schema.StringAttribute{
+							Computed:    true,
+							Description: "The mount source on the host, e.g. the volume's mountpoint",
+						},
+						"destination": schema.StringAttribute{
+							Computed:    true,
+							Description: "The path the mount is mounted at inside the container",
+						},
+						"mode": schema.StringAttribute{
+							Computed:    true,
+							Description: "The mount options the mount was requested with, e.g. \"ro,z\"",
+						},
+						"rw": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the mount is writable",
+						},
+						"propagation": schema.StringAttribute{
+							Computed:    true,
+							Description: "The bind propagation mode (rprivate, shared, ...); empty for other mount types",
+						},
+					},
+				},
+			},
+
+			"ports": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The exposed ports of the container, one entry per host binding, sorted by private port",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip": schema.StringAttribute{
+							Computed:    true,
+							Description: "The host IP the port is published on; null when the port is not published",
+						},
+						"private_port": schema.Int32Attribute{
+							Computed:    true,
+							Description: "The port inside the container",
+						},
+						"public_port": schema.Int32Attribute{
+							Computed:    true,
+							Description: "The host port the port is published on; null when the port is not published",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The port protocol (tcp, udp or sctp)",
+						},
+					},
+				},
+			},
+
+			"network_settings": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "The container's addresses on each network it is connected to, keyed by network name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ip_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "The IPv4 address of the container on the network",
+						},
+						"gateway": schema.StringAttribute{
+							Computed:    true,
+							Description: "The IPv4 gateway of the network",
+						},
+						"mac_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "The MAC address of the container's endpoint on the network",
+						},
+						"aliases": schema.ListAttribute{
+							Computed:    true,
+							Description: "The DNS aliases of the container on the network",
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+
+			"health": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The container's healthcheck state; null when the container has no healthcheck",
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						Computed:    true,
+						Description: "The health status (starting, healthy or unhealthy)",
+					},
+					"failing_streak": schema.Int64Attribute{
+						Computed:    true,
+						Description: "The number of consecutive failed healthchecks",
+					},
+					"log": schema.ListNestedAttribute{
+						Computed:    true,
+						Description: "The most recent healthcheck results kept by the daemon, oldest first",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"start": schema.StringAttribute{
+									Computed:    true,
+									Description: "The time the healthcheck started, in RFC3339 format",
+								},
+								"end": schema.StringAttribute{
+									Computed:    true,
+									Description: "The time the healthcheck ended, in RFC3339 format",
+								},
+								"exit_code": schema.Int64Attribute{
+									Computed:    true,
+									Description: "The exit code of the healthcheck; 0 is healthy",
+								},
+								"output": schema.StringAttribute{
+									Computed:    true,
+									Description: "The output of the healthcheck, e.g. why it failed",
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"raw_json": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				MarkdownDescription: `
+					The full inspect response as JSON, for fields not mapped to
+					attributes, e.g. jsondecode(data.docker_container.example.raw_json).State.Pid.
+					Sensitive, as it includes the environment and may contain secrets.
+				`,
+			},
+		},
+	}
+}
+
+func (d *ContainerDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+}
+
+func (d *ContainerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ContainerDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate container name
+	if err := validateContainerName(data.Container.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Container Name",
+			err.Error(),
+		)
+		return
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
+	err = wrapNotFound("container", data.Container.ValueString(), err)
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		resp.Diagnostics.AddError(
+			"Container Not Found",
+			err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Container",
+			fmt.Sprintf("Error inspecting container %q: %v", data.Container.ValueString(), err),
+		)
+		return
+	}
+
+	rawJSON, err := json.Marshal(inspect)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Encode Container",
+			fmt.Sprintf("Error encoding the inspect response of container %q: %v", data.Container.ValueString(), err),
+		)
+		return
+	}
+
+	data.RawJSON = types.StringValue(string(rawJSON))
+
+	data.ID = types.StringValue(inspect.ID)
+	data.Name = types.StringValue(strings.TrimPrefix(inspect.Name, "/"))
+	data.Image = types.StringValue("")
+
+	envValues := []attr.Value{}
+	envMap := map[string]attr.Value{}
+	if inspect.Config != nil {
+		data.Image = types.StringValue(inspect.Config.Image)
+
+		for _, variable := range inspect.Config.Env {
+			envValues = append(envValues, types.StringValue(variable))
+
+			// values may themselves contain "=", so only the first one separates the name
+			name, value, _ := strings.Cut(variable, "=")
+			envMap[name] = types.StringValue(value)
+		}
+	}
+
+	data.Env = types.ListValueMust(types.StringType, envValues)
+	data.EnvMap = types.MapValueMust(types.StringType, envMap)
+
+	mountValues := []attr.Value{}
+	for _, mount := range inspect.Mounts {
+		mountValues = append(mountValues, types.ObjectValueMust(
+			containerMountAttrTypes,
+			map[string]attr.Value{
+				"type":        types.StringValue(string(mount.Type)),
+				"source":      types.StringValue(mount.Source),
+				"destination": types.StringValue(mount.Destination),
+				"mode":        types.StringValue(mount.Mode),
+				"rw":          types.BoolValue(mount.RW),
+				"propagation": types.StringValue(string(mount.Propagation)),
+			},
+		))
+	}
+
+	data.Mounts = types.ListValueMust(
+		types.ObjectType{AttrTypes: containerMountAttrTypes},
+		mountValues,
+	)
+
+	var portMap nat.PortMap
+	networkValues := map[string]attr.Value{}
+
+	if inspect.NetworkSettings != nil {
+		portMap = inspect.NetworkSettings.Ports
+
+		for name, endpoint := range inspect.NetworkSettings.Networks {
+			if endpoint == nil {
+				continue
+			}
+
+			aliases := []attr.Value{}
+			for _, alias := range endpoint.Aliases {
+				aliases = append(aliases, types.StringValue(alias))
+			}
+
+			networkValues[name] = types.ObjectValueMust(
+				containerNetworkAttrTypes,
+				map[string]attr.Value{
+					"ip_address":  types.StringValue(endpoint.IPAddress),
+					"gateway":     types.StringValue(endpoint.Gateway),
+					"mac_address": types.StringValue(endpoint.MacAddress),
+					"aliases":     types.ListValueMust(types.StringType, aliases),
+				},
+			)
+		}
+	}
+
+	data.NetworkSettings = types.MapValueMust(
+		types.ObjectType{AttrTypes: containerNetworkAttrTypes},
+		networkValues,
+	)
+
+	data.Ports = types.ListValueMust(
+		types.ObjectType{AttrTypes: containerPortAttrTypes},
+		containerPortValues(portMap),
+	)
+
+	data.Health = types.ObjectNull(containerHealthAttrTypes)
+	if inspect.State != nil && inspect.State.Health != nil {
+		data.Health = containerHealthValue(inspect.State.Health)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// containerPortValues flattens a port map into one value per host binding,
+// sorted by private port and protocol. Exposed ports that are not published
+// get a single value with a null ip and public_port.
+func containerPortValues(portMap nat.PortMap) []attr.Value {
+	ports := slices.SortedFunc(maps.Keys(portMap), func(a, b nat.Port) int {
+		return cmp.Or(cmp.Compare(a.Int(), b.Int()), cmp.Compare(a.Proto(), b.Proto()))
+	})
+
+	portValues := []attr.Value{}
+	for _, port := range ports {
+		privatePort := types.Int32Value(int32(port.Int()))
+		protocol := types.StringValue(port.Proto())
+
+		bindings := portMap[port]
+		if len(bindings) == 0 {
+			portValues = append(portValues, types.ObjectValueMust(
+				containerPortAttrTypes,
+				map[string]attr.Value{
+					"ip":           types.StringNull(),
+					"private_port": privatePort,
+					"public_port":  types.Int32Null(),
+					"type":         protocol,
+				},
+			))
+			continue
+		}
+
+		for _, binding := range bindings {
+			publicPort := types.Int32Null()
+			if hostPort, err := strconv.Atoi(binding.HostPort); err == nil {
+				publicPort = types.Int32Value(int32(hostPort))
+			}
+
+			portValues = append(portValues, types.ObjectValueMust(
+				containerPortAttrTypes,
+				map[string]attr.Value{
+					"ip":           types.StringValue(binding.HostIP),
+					"private_port": privatePort,
+					"public_port":  publicPort,
+					"type":         protocol,
+				},
+			))
+		}
+	}
+
+	return portValues
+}
+
+// containerHealthValue converts a container's healthcheck state, including
+// the results the daemon keeps of the last few healthchecks.
+func containerHealthValue(health *container.Health) types.Object {
+	logValues := []attr.Value{}
+	for _, result := range health.Log {
+		if result == nil {
+			continue
+		}
+
+		logValues = append(logValues, types.ObjectValueMust(
+			containerHealthLogAttrTypes,
+			map[string]attr.Value{
+				"start":     types.StringValue(result.Start.Format(time.RFC3339Nano)),
+				"end":       types.StringValue(result.End.Format(time.RFC3339Nano)),
+				"exit_code": types.Int64Value(int64(result.ExitCode)),
+				"output":    types.StringValue(result.Output),
+			},
+		))
+	}
+
+	return types.ObjectValueMust(
+		containerHealthAttrTypes,
+		map[string]attr.Value{
+			"status":         types.StringValue(health.Status),
+			"failing_streak": types.Int64Value(int64(health.FailingStreak)),
+			"log":            types.ListValueMust(types.ObjectType{AttrTypes: containerHealthLogAttrTypes}, logValues),
+		},
+	)
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ContainerExistsDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+}
+
+type ContainerExistsDataSourceModel struct {
+	Container types.String `tfsdk:"container"`
+	Host      types.String `tfsdk:"host"`
+	Exists    types.Bool   `tfsdk:"exists"`
+	ID        types.String `tfsdk:"id"`
+}
+
+func NewContainerExistsDataSource() datasource.DataSource {
+	return &ContainerExistsDataSource{}
+}
+
+func (d *ContainerExistsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_exists"
+}
+
+func (d *ContainerExistsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Check whether a docker container exists, running or not.
+
+			Unlike other data sources, a missing container is not an error, so the
+			result can drive conditional resources. Other daemon errors still fail.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"container": schema.StringAttribute{
+				Required:    true,
+				Description: "The name or ID of the container",
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			// Computed
+
+			"exists": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the container exists",
+			},
+
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The container ID; null when the container does not exist",
+			},
+		},
+	}
+}
+
+func (d *ContainerExistsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+}
+
+func (d *ContainerExistsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ContainerExistsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate container name
+	if err := validateContainerName(data.Container.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Container Name",
+			err.Error(),
+		)
+		return
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
+	if cerrdefs.IsNotFound(err) {
+		data.Exists = types.BoolValue(false)
+		data.ID = types.StringNull()
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Container",
+			fmt.Sprintf("Error inspecting container %q: %v", data.Container.ValueString(), err),
+		)
+		return
+	}
+
+	data.Exists = types.BoolValue(true)
+	data.ID = types.StringValue(inspect.ID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// checkContainerExists inspects a container before other requests are made
+// for it, returning a NotFoundError when it does not exist. Requests such as
+// CopyFromContainer otherwise fail partway with a less specific daemon error.
+func checkContainerExists(ctx context.Context, dockerClient *client.Client, containerName string) error {
+	_, err := dockerClient.ContainerInspect(ctx, containerName)
+	return wrapNotFound("container", containerName, err)
+}
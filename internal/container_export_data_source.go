@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultExportMaxBytes is the largest export written when max_bytes is unset (1GB)
+const DefaultExportMaxBytes = 1024 * 1024 * 1024
+
+type ContainerExportDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+}
+
+type ContainerExportDataSourceModel struct {
+	Container  types.String `tfsdk:"container"`
+	Host       types.String `tfsdk:"host"`
+	OutputPath types.String `tfsdk:"output_path"`
+	MaxBytes   types.Int64  `tfsdk:"max_bytes"`
+	Size       types.Int64  `tfsdk:"size"`
+	SHA256     types.String `tfsdk:"sha256"`
+}
+
+func NewContainerExportDataSource() datasource.DataSource {
+	return &ContainerExportDataSource{}
+}
+
+func (d *ContainerExportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_export"
+}
+
+func (d *ContainerExportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Export a docker container's entire filesystem as a tar archive to a
+			local file, e.g. to archive its state for forensics.
+
+			The archive is streamed to disk and never stored in state. Volumes are
+			not part of the export. The file is rewritten on every read.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"container": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the container",
+			},
+
+			"output_path": schema.StringAttribute{
+				Required:    true,
+				Description: "The local file to write the tar archive to; replaced only once the export completes",
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"max_bytes": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum size of the archive; the export fails and nothing is
+					written when the container's filesystem is larger
+
+					Default: 1073741824 (1GB)
+				`,
+			},
+
+			// Computed
+
+			"size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The size of the archive in bytes",
+			},
+
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "The hex-encoded SHA-256 checksum of the archive",
+			},
+		},
+	}
+}
+
+func (d *ContainerExportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+}
+
+func (d *ContainerExportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ContainerExportDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate container name
+	if err := validateContainerName(data.Container.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Container Name",
+			err.Error(),
+		)
+		return
+	}
+
+	if data.OutputPath.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Output Path",
+			"output_path cannot be empty",
+		)
+		return
+	}
+
+	maxBytes := int64(DefaultExportMaxBytes)
+	if !data.MaxBytes.IsNull() {
+		maxBytes = data.MaxBytes.ValueInt64()
+	}
+
+	if maxBytes < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Max Bytes",
+			fmt.Sprintf("max_bytes must be at least 1, got: %d", maxBytes),
+		)
+		return
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	export, err := dockerClient.ContainerExport(ctx, data.Container.ValueString())
+	err = wrapNotFound("container", data.Container.ValueString(), err)
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		resp.Diagnostics.AddError(
+			"Container Not Found",
+			err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Export Container",
+			formatError("export", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := export.Close(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				formatError("close", fmt.Sprintf("export stream of container %q", data.Container.ValueString()), "", "", closeErr),
+			)
+		}
+	}()
+
+	size, checksum, err := writeExport(newContextReader(ctx, export), data.OutputPath.ValueString(), maxBytes)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Write Container Export",
+			formatError("write", fmt.Sprintf("export of container %q to %q", data.Container.ValueString(), data.OutputPath.ValueString()), "", "", err),
+		)
+		return
+	}
+
+	data.Size = types.Int64Value(size)
+	data.SHA256 = types.StringValue(hex.EncodeToString(checksum))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// writeExport streams an export archive to a temporary file next to
+// outputPath and renames it into place once complete, so a failed or
+// oversized export never leaves a partial archive behind. It returns the
+// archive's size and SHA-256.
+func writeExport(r io.Reader, outputPath string, maxBytes int64) (int64, []byte, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".*")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	// one byte past the limit tells an oversized export apart from one that fits exactly
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if size > maxBytes {
+		return 0, nil, fmt.Errorf("archive exceeds max_bytes of %d bytes", maxBytes)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return 0, nil, err
+	}
+
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return 0, nil, err
+	}
+
+	return size, hash.Sum(nil), nil
+}
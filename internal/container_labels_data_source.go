@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ContainerLabelsDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+}
+
+type ContainerLabelsDataSourceModel struct {
+	Container   types.String `tfsdk:"container"`
+	Host        types.String `tfsdk:"host"`
+	LabelPrefix types.String `tfsdk:"label_prefix"`
+	StripPrefix types.Bool   `tfsdk:"strip_prefix"`
+	Labels      types.Map    `tfsdk:"labels"`
+}
+
+func NewContainerLabelsDataSource() datasource.DataSource {
+	return &ContainerLabelsDataSource{}
+}
+
+func (d *ContainerLabelsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_labels"
+}
+
+func (d *ContainerLabelsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Retrieve the labels of a docker container, optionally only those under
+			a key prefix.
+
+			Only the labels are kept in state, unlike docker_container, which is
+			useful when orchestrators inject dozens of labels.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"container": schema.StringAttribute{
+				Required:    true,
+				Description: "The name or ID of the container",
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"label_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return labels whose keys start with this prefix, e.g. com.example.",
+			},
+
+			"strip_prefix": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to remove label_prefix from the keys of labels",
+			},
+
+			// Computed
+
+			"labels": schema.MapAttribute{
+				Computed:    true,
+				Description: "The container's labels, including those inherited from its image",
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ContainerLabelsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+}
+
+func (d *ContainerLabelsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ContainerLabelsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate container name
+	if err := validateContainerName(data.Container.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Container Name",
+			err.Error(),
+		)
+		return
+	}
+
+	if data.StripPrefix.ValueBool() && data.LabelPrefix.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Label Prefix",
+			"strip_prefix requires a non-empty label_prefix",
+		)
+		return
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
+	err = wrapNotFound("container", data.Container.ValueString(), err)
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		resp.Diagnostics.AddError(
+			"Container Not Found",
+			err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Container",
+			fmt.Sprintf("Error inspecting container %q: %v", data.Container.ValueString(), err),
+		)
+		return
+	}
+
+	labels := map[string]attr.Value{}
+	if inspect.Config != nil {
+		prefix := data.LabelPrefix.ValueString()
+
+		for key, value := range inspect.Config.Labels {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+
+			if data.StripPrefix.ValueBool() {
+				key = strings.TrimPrefix(key, prefix)
+			}
+
+			labels[key] = types.StringValue(value)
+		}
+	}
+
+	data.Labels = types.MapValueMust(types.StringType, labels)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
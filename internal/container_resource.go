@@ -0,0 +1,1185 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Container wait conditions
+const (
+	// ContainerWaitRunning waits until the container reports State.Running
+	ContainerWaitRunning = "running"
+	// ContainerWaitHealthy waits until the container's healthcheck reports healthy
+	ContainerWaitHealthy = "healthy"
+	// ContainerRemoveConflictTimeout bounds retrying a removal the daemon
+	// rejects with a conflict, e.g. while the container is still stopping
+	ContainerRemoveConflictTimeout = 30 * time.Second
+)
+
+// containerHealthcheckAttrTypes are the attribute types of a docker_container healthcheck
+var containerHealthcheckAttrTypes = map[string]attr.Type{
+	"test":         types.ListType{ElemType: types.StringType},
+	"interval":     types.Int32Type,
+	"timeout":      types.Int32Type,
+	"start_period": types.Int32Type,
+	"retries":      types.Int32Type,
+}
+
+// containerResourceNetworkAttrTypes are the attribute types of a docker_container network attachment
+var containerResourceNetworkAttrTypes = map[string]attr.Type{
+	"name":         types.StringType,
+	"aliases":      types.ListType{ElemType: types.StringType},
+	"ipv4_address": types.StringType,
+}
+
+// containerDeviceAttrTypes are the attribute types of a docker_container device mapping
+var containerDeviceAttrTypes = map[string]attr.Type{
+	"host_path":      types.StringType,
+	"container_path": types.StringType,
+	"permissions":    types.StringType,
+}
+
+// containerUlimitAttrTypes are the attribute types of a docker_container ulimit
+var containerUlimitAttrTypes = map[string]attr.Type{
+	"name": types.StringType,
+	"soft": types.Int64Type,
+	"hard": types.Int64Type,
+}
+
+type ContainerResource struct {
+	DockerClient *client.Client
+}
+
+type ContainerResourceModel struct {
+	ID          types.String  `tfsdk:"id"`
+	Name        types.String  `tfsdk:"name"`
+	Image       types.String  `tfsdk:"image"`
+	Command     types.List    `tfsdk:"command"`
+	Env         types.List    `tfsdk:"env"`
+	Healthcheck types.Object  `tfsdk:"healthcheck"`
+	Networks    types.List    `tfsdk:"networks"`
+	Devices     types.List    `tfsdk:"devices"`
+	Ulimits     types.List    `tfsdk:"ulimits"`
+	CapAdd      types.List    `tfsdk:"cap_add"`
+	CapDrop     types.List    `tfsdk:"cap_drop"`
+	Memory      types.Int64   `tfsdk:"memory"`
+	MemorySwap  types.Int64   `tfsdk:"memory_swap"`
+	CPUShares   types.Int64   `tfsdk:"cpu_shares"`
+	CPUs        types.Float64 `tfsdk:"cpus"`
+	CPUSetCPUs  types.String  `tfsdk:"cpuset_cpus"`
+	LogDriver   types.String  `tfsdk:"log_driver"`
+	LogOpts     types.Map     `tfsdk:"log_opts"`
+	MustRun     types.Bool    `tfsdk:"must_run"`
+	Triggers    types.Map     `tfsdk:"restart_triggers"`
+	WaitFor     types.String  `tfsdk:"wait_for"`
+	WaitTimeout types.Int32   `tfsdk:"wait_timeout"`
+	CaptureLogs types.Bool    `tfsdk:"capture_logs"`
+	LogLines    types.Int32   `tfsdk:"capture_logs_lines"`
+	Logs        types.List    `tfsdk:"logs"`
+}
+
+type ContainerHealthcheckModel struct {
+	Test        []string    `tfsdk:"test"`
+	Interval    types.Int32 `tfsdk:"interval"`
+	Timeout     types.Int32 `tfsdk:"timeout"`
+	StartPeriod types.Int32 `tfsdk:"start_period"`
+	Retries     types.Int32 `tfsdk:"retries"`
+}
+
+type ContainerNetworkModel struct {
+	Name        types.String `tfsdk:"name"`
+	Aliases     []string     `tfsdk:"aliases"`
+	IPv4Address types.String `tfsdk:"ipv4_address"`
+}
+
+type ContainerDeviceModel struct {
+	HostPath      types.String `tfsdk:"host_path"`
+	ContainerPath types.String `tfsdk:"container_path"`
+	Permissions   types.String `tfsdk:"permissions"`
+}
+
+type ContainerUlimitModel struct {
+	Name types.String `tfsdk:"name"`
+	Soft types.Int64  `tfsdk:"soft"`
+	Hard types.Int64  `tfsdk:"hard"`
+}
+
+func NewContainerResource() resource.Resource {
+	return &ContainerResource{}
+}
+
+func (r *ContainerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container"
+}
+
+func (r *ContainerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Create and start a docker container.
+
+			The image must already be present on the docker daemon. Changing any
+			container setting other than the wait options replaces the container.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the container",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"image": schema.StringAttribute{
+				Required:    true,
+				Description: "The image to create the container from",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Optional
+
+			"command": schema.ListAttribute{
+				Optional:    true,
+				Description: "The command to run, overriding the image's default command",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"env": schema.ListAttribute{
+				Optional:    true,
+				Description: "Environment variables to set, in KEY=VALUE form",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"healthcheck": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "The healthcheck to run, overriding the image's healthcheck",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"test": schema.ListAttribute{
+						Required:    true,
+						Description: "The test to perform, e.g. [\"CMD\", \"curl\", \"-f\", \"http://localhost\"]",
+						ElementType: types.StringType,
+					},
+					"interval": schema.Int32Attribute{
+						Optional:    true,
+						Description: "Seconds to wait between checks",
+					},
+					"timeout": schema.Int32Attribute{
+						Optional:    true,
+						Description: "Seconds to wait before considering a check hung",
+					},
+					"start_period": schema.Int32Attribute{
+						Optional:    true,
+						Description: "Seconds to allow the container to initialize before failures count",
+					},
+					"retries": schema.Int32Attribute{
+						Optional:    true,
+						Description: "Consecutive failures needed to report unhealthy",
+					},
+				},
+			},
+
+			"networks": schema.ListNestedAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The networks to attach the container to, in order. The container is
+					created on the first network and connected to the others before it
+					is started. When unset, the daemon's default network is used.
+				`,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "The name or ID of the network",
+						},
+						"aliases": schema.ListAttribute{
+							Optional:    true,
+							Description: "DNS aliases of the container on the network",
+							ElementType: types.StringType,
+						},
+						"ipv4_address": schema.StringAttribute{
+							Optional:    true,
+							Description: "A static IPv4 address for the container on the network; the network must have a user-configured subnet",
+						},
+					},
+				},
+			},
+
+			"devices": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Host devices to make available in the container, e.g. /dev/fuse",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host_path": schema.StringAttribute{
+							Required:    true,
+							Description: "The path of the device on the host",
+						},
+						"container_path": schema.StringAttribute{
+							Required:    true,
+							Description: "The path the device is available at in the container",
+						},
+						"permissions": schema.StringAttribute{
+							Optional: true,
+							Computed: true,
+							Default:  stringdefault.StaticString("rwm"),
+							MarkdownDescription: `
+								The cgroup permissions of the container on the device: any of r
+								(read), w (write) and m (mknod)
+
+								Default: "rwm"
+							`,
+						},
+					},
+				},
+			},
+
+			"ulimits": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Resource limits to set in the container, overriding the daemon's defaults",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "The name of the limit, e.g. nofile or memlock",
+						},
+						"soft": schema.Int64Attribute{
+							Required:    true,
+							Description: "The soft limit; -1 is unlimited",
+						},
+						"hard": schema.Int64Attribute{
+							Required:    true,
+							Description: "The hard limit; -1 is unlimited",
+						},
+					},
+				},
+			},
+
+			"cap_add": schema.ListAttribute{
+				Optional:    true,
+				Description: "Linux capabilities to add to the container, e.g. NET_ADMIN",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"cap_drop": schema.ListAttribute{
+				Optional:    true,
+				Description: "Linux capabilities to drop from the container, e.g. ALL",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The memory limit of the container in bytes",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+
+			"memory_swap": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The limit of memory plus swap in bytes, at least memory; -1 allows unlimited swap. Requires memory",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+
+			"cpu_shares": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The relative CPU weight of the container against other containers (the daemon's default is 1024)",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+
+			"cpus": schema.Float64Attribute{
+				Optional:    true,
+				Description: "The number of CPUs the container may use, e.g. 1.5",
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.RequiresReplace(),
+				},
+			},
+
+			"cpuset_cpus": schema.StringAttribute{
+				Optional:    true,
+				Description: "The CPUs the container may run on, e.g. 0-3 or 0,1",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"log_driver": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The logging driver of the container, e.g. json-file, journald or
+					none. Logs can only be read back by capture_logs and the docker_logs
+					data source for drivers the daemon can read from, such as json-file,
+					local and journald; with none no logs are kept at all.
+
+					Default: the daemon's default logging driver
+				`,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"log_opts": schema.MapAttribute{
+				Optional:    true,
+				Description: "Options of the logging driver, e.g. max-size for json-file",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"must_run": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to start the container again on apply when it has stopped, e.g. after exiting or a manual docker stop",
+			},
+
+			"restart_triggers": schema.MapAttribute{
+				Optional:    true,
+				Description: "Arbitrary values that replace the container when changed, e.g. the checksum of a bind-mounted config file",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"wait_for": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The state to wait for after starting the container, either
+					"running" or "healthy". When unset, creation returns as soon
+					as the container has been started.
+				`,
+			},
+
+			"wait_timeout": schema.Int32Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int32default.StaticInt32(60),
+				MarkdownDescription: `
+					Seconds to wait for the wait_for state
+
+					Default: 60 seconds
+				`,
+			},
+
+			"capture_logs": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to capture the container's output into logs once it has started (and been waited for)",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"capture_logs_lines": schema.Int32Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int32default.StaticInt32(100),
+				MarkdownDescription: `
+					The number of most recent log lines to capture
+
+					Default: 100 lines
+				`,
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed
+
+			"logs": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The captured logs of the container, when capture_logs is set",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"stdout": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the log is from stdout",
+						},
+						"stderr": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the log is from stderr",
+						},
+						"message": schema.StringAttribute{
+							Computed:    true,
+							Description: "The log message",
+						},
+						"timestamp": schema.StringAttribute{
+							Computed:    true,
+							Description: "The log timestamp",
+						},
+					},
+				},
+			},
+
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The container ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ContainerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.DockerClient = config.DockerClient
+}
+
+func (r *ContainerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ContainerResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Name.IsUnknown() {
+		if err := validateContainerName(data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid Container Name",
+				err.Error(),
+			)
+		} else if strings.HasPrefix(data.Name.ValueString(), "/") {
+			// the name is read back without the slash, which would show as drift
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid Container Name",
+				fmt.Sprintf("Container name must not start with a slash, got: %s", data.Name.ValueString()),
+			)
+		}
+	}
+
+	if !data.MemorySwap.IsNull() && !data.MemorySwap.IsUnknown() && data.MemorySwap.ValueInt64() != -1 {
+		switch {
+		case data.Memory.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("memory_swap"),
+				"Invalid Memory Swap Limit",
+				"memory_swap can only be set together with memory",
+			)
+		case !data.Memory.IsUnknown() && data.MemorySwap.ValueInt64() < data.Memory.ValueInt64():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("memory_swap"),
+				"Invalid Memory Swap Limit",
+				fmt.Sprintf("memory_swap must be at least memory (%d) or -1, got: %d", data.Memory.ValueInt64(), data.MemorySwap.ValueInt64()),
+			)
+		}
+	}
+
+	if !data.CPUs.IsNull() && !data.CPUs.IsUnknown() && data.CPUs.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cpus"),
+			"Invalid CPU Limit",
+			fmt.Sprintf("cpus must be greater than 0, got: %g", data.CPUs.ValueFloat64()),
+		)
+	}
+
+	switch data.WaitFor.ValueString() {
+	case "", ContainerWaitRunning, ContainerWaitHealthy:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("wait_for"),
+			"Invalid Wait Condition",
+			fmt.Sprintf("wait_for must be %q or %q, got: %q", ContainerWaitRunning, ContainerWaitHealthy, data.WaitFor.ValueString()),
+		)
+	}
+}
+
+func (r *ContainerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ContainerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := &container.Config{
+		Image: data.Image.ValueString(),
+	}
+
+	resp.Diagnostics.Append(data.Command.ElementsAs(ctx, &config.Cmd, false)...)
+	resp.Diagnostics.Append(data.Env.ElementsAs(ctx, &config.Env, false)...)
+
+	if !data.Healthcheck.IsNull() {
+		var healthcheck ContainerHealthcheckModel
+		resp.Diagnostics.Append(data.Healthcheck.As(ctx, &healthcheck, basetypes.ObjectAsOptions{})...)
+
+		config.Healthcheck = &container.HealthConfig{
+			Test:        healthcheck.Test,
+			Interval:    time.Duration(healthcheck.Interval.ValueInt32()) * time.Second,
+			Timeout:     time.Duration(healthcheck.Timeout.ValueInt32()) * time.Second,
+			StartPeriod: time.Duration(healthcheck.StartPeriod.ValueInt32()) * time.Second,
+			Retries:     int(healthcheck.Retries.ValueInt32()),
+		}
+	}
+
+	var networks []ContainerNetworkModel
+	resp.Diagnostics.Append(data.Networks.ElementsAs(ctx, &networks, false)...)
+
+	hostConfig := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:     data.Memory.ValueInt64(),
+			MemorySwap: data.MemorySwap.ValueInt64(),
+			CPUShares:  data.CPUShares.ValueInt64(),
+			NanoCPUs:   int64(data.CPUs.ValueFloat64() * 1e9),
+			CpusetCpus: data.CPUSetCPUs.ValueString(),
+		},
+	}
+
+	hostConfig.LogConfig.Type = data.LogDriver.ValueString()
+	resp.Diagnostics.Append(data.LogOpts.ElementsAs(ctx, &hostConfig.LogConfig.Config, false)...)
+
+	resp.Diagnostics.Append(data.CapAdd.ElementsAs(ctx, &hostConfig.CapAdd, false)...)
+	resp.Diagnostics.Append(data.CapDrop.ElementsAs(ctx, &hostConfig.CapDrop, false)...)
+
+	var devices []ContainerDeviceModel
+	resp.Diagnostics.Append(data.Devices.ElementsAs(ctx, &devices, false)...)
+
+	for _, device := range devices {
+		hostConfig.Devices = append(hostConfig.Devices, container.DeviceMapping{
+			PathOnHost:        device.HostPath.ValueString(),
+			PathInContainer:   device.ContainerPath.ValueString(),
+			CgroupPermissions: device.Permissions.ValueString(),
+		})
+	}
+
+	var ulimits []ContainerUlimitModel
+	resp.Diagnostics.Append(data.Ulimits.ElementsAs(ctx, &ulimits, false)...)
+
+	for _, ulimit := range ulimits {
+		hostConfig.Ulimits = append(hostConfig.Ulimits, &container.Ulimit{
+			Name: ulimit.Name.ValueString(),
+			Soft: ulimit.Soft.ValueInt64(),
+			Hard: ulimit.Hard.ValueInt64(),
+		})
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ContainerCreate only attaches a single network, so the others are
+	// connected once the container exists
+	var networkingConfig *network.NetworkingConfig
+
+	if len(networks) > 0 {
+		hostConfig.NetworkMode = container.NetworkMode(networks[0].Name.ValueString())
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				networks[0].Name.ValueString(): containerEndpointSettings(networks[0]),
+			},
+		}
+	}
+
+	created, err := r.DockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Container",
+			fmt.Sprintf("Error creating container %q from image %q: %v", data.Name.ValueString(), data.Image.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the container as soon as it exists so a failed start or wait
+	// leaves it tainted in state rather than orphaned on the daemon.
+	data.ID = types.StringValue(created.ID)
+	data.Logs = types.ListNull(types.ObjectType{AttrTypes: logLineAttrTypes})
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	for i := 1; i < len(networks); i++ {
+		err := r.DockerClient.NetworkConnect(ctx, created.ID, networks[i].Name.ValueString(), containerEndpointSettings(networks[i]))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Connect Container to Network",
+				fmt.Sprintf("Error connecting container %q to network %q: %v", data.Name.ValueString(), networks[i].Name.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	if err := r.DockerClient.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Start Container",
+			fmt.Sprintf("Error starting container %q: %v", data.Name.ValueString(), err),
+		)
+		return
+	}
+
+	if data.WaitFor.ValueString() != "" {
+		timeout := time.Duration(data.WaitTimeout.ValueInt32()) * time.Second
+		if err := waitForContainer(ctx, r.DockerClient, created.ID, data.WaitFor.ValueString(), timeout); err != nil {
+			resp.Diagnostics.AddError(
+				"Container Did Not Become Ready",
+				fmt.Sprintf("Error waiting for container %q to be %s: %v", data.Name.ValueString(), data.WaitFor.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	if data.CaptureLogs.ValueBool() {
+		logLines, err := captureContainerLogs(ctx, r.DockerClient, created.ID, int(data.LogLines.ValueInt32()))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Capture Container Logs",
+				fmt.Sprintf("Error reading logs for container %q: %v", data.Name.ValueString(), err),
+			)
+			return
+		}
+
+		logValues := []attr.Value{}
+		for _, line := range logLines {
+			logValues = append(logValues, types.ObjectValueMust(logLineAttrTypes, line.attrValues()))
+		}
+
+		data.Logs = types.ListValueMust(
+			types.ObjectType{AttrTypes: logLineAttrTypes},
+			logValues,
+		)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContainerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ContainerResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inspect, err := r.DockerClient.ContainerInspect(ctx, data.ID.ValueString())
+	if cerrdefs.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Container",
+			fmt.Sprintf("Error inspecting container %q: %v", data.ID.ValueString(), err),
+		)
+		return
+	}
+
+	// name is required, so it is only null right after an import
+	if data.Name.IsNull() {
+		resp.Diagnostics.Append(readImportedContainer(ctx, r.DockerClient, inspect, &data)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	data.Name = types.StringValue(strings.TrimPrefix(inspect.Name, "/"))
+	if inspect.Config != nil {
+		data.Image = types.StringValue(inspect.Config.Image)
+	}
+
+	if data.MustRun.ValueBool() && inspect.State != nil && !inspect.State.Running {
+		data.MustRun = types.BoolValue(false)
+	}
+
+	if inspect.HostConfig != nil {
+		resp.Diagnostics.Append(readContainerHostConfig(ctx, inspect.HostConfig, &data)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Networks the container was disconnected from are dropped, so the plan
+	// replaces the container to reattach them. The attachment settings are
+	// kept as configured, as the daemon adds aliases of its own.
+	if !data.Networks.IsNull() {
+		var networks []ContainerNetworkModel
+		resp.Diagnostics.Append(data.Networks.ElementsAs(ctx, &networks, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		attached := []ContainerNetworkModel{}
+		for _, n := range networks {
+			if inspect.NetworkSettings != nil && isAttachedToNetwork(inspect.NetworkSettings.Networks, n.Name.ValueString()) {
+				attached = append(attached, n)
+			}
+		}
+
+		var diags diag.Diagnostics
+		data.Networks, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: containerResourceNetworkAttrTypes}, attached)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContainerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ContainerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every container setting requires replacement, so only the wait
+	// options and must_run can change in place. Read reports must_run as
+	// false for a stopped container, so it also shows up here when the
+	// container has to be started again.
+	if data.MustRun.ValueBool() {
+		if err := r.DockerClient.ContainerStart(ctx, data.ID.ValueString(), container.StartOptions{}); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Start Container",
+				fmt.Sprintf("Error starting container %q: %v", data.Name.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContainerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *ContainerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ContainerResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := removeContainer(ctx, r.DockerClient, data.ID.ValueString(), ContainerRemoveConflictTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Container",
+			fmt.Sprintf("Error removing container %q: %v", data.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// waitForContainer polls ContainerInspect with PollUntil until the container
+// reaches the given condition ("running" or "healthy") or the timeout elapses.
+// On timeout, the error includes the output of the most recent healthcheck.
+func waitForContainer(ctx context.Context, dockerClient *client.Client, id, condition string, timeout time.Duration) error {
+	var lastHealth *container.Health
+
+	err := PollUntil(ctx, WaitInitialInterval, WaitMaxInterval, timeout, func() (bool, error) {
+		inspect, err := dockerClient.ContainerInspect(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to inspect container: %w", err)
+		}
+
+		state := inspect.State
+		if state == nil {
+			return false, nil
+		}
+
+		if !state.Running && !state.Restarting && state.Status != container.StateCreated {
+			return false, fmt.Errorf("container is %s (exit code %d)", state.Status, state.ExitCode)
+		}
+
+		switch condition {
+		case ContainerWaitRunning:
+			return state.Running, nil
+		case ContainerWaitHealthy:
+			if state.Health == nil {
+				return false, fmt.Errorf("container has no healthcheck configured")
+			}
+			lastHealth = state.Health
+			return state.Health.Status == container.Healthy, nil
+		}
+
+		return false, nil
+	})
+
+	var timedOut *WaitTimeoutError
+	if errors.As(err, &timedOut) && lastHealth != nil && len(lastHealth.Log) > 0 {
+		last := lastHealth.Log[len(lastHealth.Log)-1]
+		return fmt.Errorf("%w (health status %q, last check exit code %d: %s)",
+			err, lastHealth.Status, last.ExitCode, strings.TrimSpace(last.Output))
+	}
+
+	return err
+}
+
+// readContainerHostConfig sets the resource limits, logging, devices, ulimits
+// and capabilities of data from a container's host config. Attributes left unset
+// keep their null value while the daemon reports none, so an unset attribute
+// doesn't show a diff against an empty list or zero limit.
+func readContainerHostConfig(ctx context.Context, hostConfig *container.HostConfig, data *ContainerResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !data.Devices.IsNull() || len(hostConfig.Devices) > 0 {
+		devices := []ContainerDeviceModel{}
+		for _, device := range hostConfig.Devices {
+			devices = append(devices, ContainerDeviceModel{
+				HostPath:      types.StringValue(device.PathOnHost),
+				ContainerPath: types.StringValue(device.PathInContainer),
+				Permissions:   types.StringValue(device.CgroupPermissions),
+			})
+		}
+
+		var d diag.Diagnostics
+		data.Devices, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: containerDeviceAttrTypes}, devices)
+		diags.Append(d...)
+	}
+
+	if !data.Ulimits.IsNull() || len(hostConfig.Ulimits) > 0 {
+		ulimits := []ContainerUlimitModel{}
+		for _, ulimit := range hostConfig.Ulimits {
+			if ulimit == nil {
+				continue
+			}
+
+			ulimits = append(ulimits, ContainerUlimitModel{
+				Name: types.StringValue(ulimit.Name),
+				Soft: types.Int64Value(ulimit.Soft),
+				Hard: types.Int64Value(ulimit.Hard),
+			})
+		}
+
+		var d diag.Diagnostics
+		data.Ulimits, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: containerUlimitAttrTypes}, ulimits)
+		diags.Append(d...)
+	}
+
+	if !data.Memory.IsNull() || hostConfig.Memory != 0 {
+		data.Memory = types.Int64Value(hostConfig.Memory)
+	}
+
+	// without memory_swap the daemon reports twice the memory limit
+	if !data.MemorySwap.IsNull() {
+		data.MemorySwap = types.Int64Value(hostConfig.MemorySwap)
+	}
+
+	if !data.CPUShares.IsNull() || hostConfig.CPUShares != 0 {
+		data.CPUShares = types.Int64Value(hostConfig.CPUShares)
+	}
+
+	if !data.CPUs.IsNull() || hostConfig.NanoCPUs != 0 {
+		data.CPUs = types.Float64Value(float64(hostConfig.NanoCPUs) / 1e9)
+	}
+
+	if !data.CPUSetCPUs.IsNull() || hostConfig.CpusetCpus != "" {
+		data.CPUSetCPUs = types.StringValue(hostConfig.CpusetCpus)
+	}
+
+	// the daemon always reports a driver and its options, even when the
+	// container uses the daemon's defaults
+	if !data.LogDriver.IsNull() {
+		data.LogDriver = types.StringValue(hostConfig.LogConfig.Type)
+	}
+
+	if !data.LogOpts.IsNull() {
+		logOpts := hostConfig.LogConfig.Config
+		if logOpts == nil {
+			logOpts = map[string]string{}
+		}
+
+		var d diag.Diagnostics
+		data.LogOpts, d = types.MapValueFrom(ctx, types.StringType, logOpts)
+		diags.Append(d...)
+	}
+
+	if !data.CapAdd.IsNull() || len(hostConfig.CapAdd) > 0 {
+		var d diag.Diagnostics
+		data.CapAdd, d = types.ListValueFrom(ctx, types.StringType, append([]string{}, hostConfig.CapAdd...))
+		diags.Append(d...)
+	}
+
+	if !data.CapDrop.IsNull() || len(hostConfig.CapDrop) > 0 {
+		var d diag.Diagnostics
+		data.CapDrop, d = types.ListValueFrom(ctx, types.StringType, append([]string{}, hostConfig.CapDrop...))
+		diags.Append(d...)
+	}
+
+	return diags
+}
+
+// readImportedContainer sets the attributes of an imported container that
+// Read otherwise only refreshes once they are configured. Settings the
+// container inherited from its image or the daemon are left unset, so a
+// configuration that doesn't set them plans no changes.
+func readImportedContainer(ctx context.Context, dockerClient *client.Client, inspect container.InspectResponse, data *ContainerResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// the container may have been imported by name
+	data.ID = types.StringValue(inspect.ID)
+	data.WaitTimeout = types.Int32Value(60)
+	data.LogLines = types.Int32Value(100)
+	data.Logs = types.ListNull(types.ObjectType{AttrTypes: logLineAttrTypes})
+
+	if inspect.Config == nil {
+		return diags
+	}
+
+	imageInspect, err := dockerClient.ImageInspect(ctx, inspect.Image)
+	if err != nil {
+		diags.AddError(
+			"Unable to Inspect Image",
+			fmt.Sprintf("Error inspecting image %q of imported container %q: %v", inspect.Config.Image, data.ID.ValueString(), err),
+		)
+		return diags
+	}
+
+	imageConfig := &container.Config{}
+	if imageInspect.Config != nil {
+		imageConfig.Cmd = imageInspect.Config.Cmd
+		imageConfig.Env = imageInspect.Config.Env
+		imageConfig.Healthcheck = imageInspect.Config.Healthcheck
+	}
+
+	var d diag.Diagnostics
+
+	if !slices.Equal(inspect.Config.Cmd, imageConfig.Cmd) {
+		data.Command, d = types.ListValueFrom(ctx, types.StringType, []string(inspect.Config.Cmd))
+		diags.Append(d...)
+	}
+
+	// the container's environment includes the image's
+	env := []string{}
+	for _, variable := range inspect.Config.Env {
+		if !slices.Contains(imageConfig.Env, variable) {
+			env = append(env, variable)
+		}
+	}
+	if len(env) > 0 {
+		data.Env, d = types.ListValueFrom(ctx, types.StringType, env)
+		diags.Append(d...)
+	}
+
+	if healthcheck := inspect.Config.Healthcheck; healthcheck != nil && !reflect.DeepEqual(healthcheck, imageConfig.Healthcheck) {
+		seconds := func(duration time.Duration) types.Int32 {
+			if duration == 0 {
+				return types.Int32Null()
+			}
+			return types.Int32Value(int32(duration / time.Second))
+		}
+
+		retries := types.Int32Null()
+		if healthcheck.Retries != 0 {
+			retries = types.Int32Value(int32(healthcheck.Retries))
+		}
+
+		data.Healthcheck, d = types.ObjectValueFrom(ctx, containerHealthcheckAttrTypes, ContainerHealthcheckModel{
+			Test:        healthcheck.Test,
+			Interval:    seconds(healthcheck.Interval),
+			Timeout:     seconds(healthcheck.Timeout),
+			StartPeriod: seconds(healthcheck.StartPeriod),
+			Retries:     retries,
+		})
+		diags.Append(d...)
+	}
+
+	if inspect.HostConfig != nil {
+		info, err := dockerClient.Info(ctx)
+		if err != nil {
+			diags.AddError(
+				"Unable to Read Daemon Info",
+				fmt.Sprintf("Error reading the default logging driver for imported container %q: %v", data.ID.ValueString(), err),
+			)
+			return diags
+		}
+
+		if inspect.HostConfig.LogConfig.Type != info.LoggingDriver {
+			data.LogDriver = types.StringValue(inspect.HostConfig.LogConfig.Type)
+		}
+
+		if len(inspect.HostConfig.LogConfig.Config) > 0 {
+			data.LogOpts, d = types.MapValueFrom(ctx, types.StringType, inspect.HostConfig.LogConfig.Config)
+			diags.Append(d...)
+		}
+	}
+
+	// Networks are only set when the container isn't just on the daemon's
+	// default network, with the network it was created on first.
+	if inspect.HostConfig != nil && inspect.NetworkSettings != nil && !inspect.HostConfig.NetworkMode.IsDefault() && !inspect.HostConfig.NetworkMode.IsBridge() {
+		primary := string(inspect.HostConfig.NetworkMode)
+		names := slices.Sorted(maps.Keys(inspect.NetworkSettings.Networks))
+		if i := slices.Index(names, primary); i > 0 {
+			names = append([]string{primary}, slices.Delete(names, i, i+1)...)
+		}
+
+		networks := []ContainerNetworkModel{}
+		for _, name := range names {
+			n := ContainerNetworkModel{Name: types.StringValue(name), IPv4Address: types.StringNull()}
+
+			if endpoint := inspect.NetworkSettings.Networks[name]; endpoint != nil {
+				if len(endpoint.Aliases) > 0 {
+					n.Aliases = endpoint.Aliases
+				}
+				if endpoint.IPAMConfig != nil && endpoint.IPAMConfig.IPv4Address != "" {
+					n.IPv4Address = types.StringValue(endpoint.IPAMConfig.IPv4Address)
+				}
+			}
+
+			networks = append(networks, n)
+		}
+
+		data.Networks, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: containerResourceNetworkAttrTypes}, networks)
+		diags.Append(d...)
+	}
+
+	return diags
+}
+
+// containerEndpointSettings returns the endpoint settings attaching a
+// container to a network.
+func containerEndpointSettings(n ContainerNetworkModel) *network.EndpointSettings {
+	settings := &network.EndpointSettings{Aliases: n.Aliases}
+
+	if n.IPv4Address.ValueString() != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: n.IPv4Address.ValueString()}
+	}
+
+	return settings
+}
+
+// isAttachedToNetwork reports whether a container's endpoints, keyed by
+// network name, include the network given by name, ID or ID prefix.
+func isAttachedToNetwork(endpoints map[string]*network.EndpointSettings, nameOrID string) bool {
+	if _, ok := endpoints[nameOrID]; ok {
+		return true
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint != nil && endpoint.NetworkID != "" && strings.HasPrefix(endpoint.NetworkID, nameOrID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeContainer force-removes a container, retrying with backoff while the
+// daemon rejects the removal with a conflict, such as when a stop or another
+// removal is already in progress. A container that no longer exists counts as
+// removed.
+func removeContainer(ctx context.Context, dockerClient *client.Client, id string, timeout time.Duration) error {
+	var conflict error
+
+	err := PollUntil(ctx, WaitInitialInterval, WaitMaxInterval, timeout, func() (bool, error) {
+		err := dockerClient.ContainerRemove(ctx, id, container.RemoveOptions{Force: true})
+		switch {
+		case err == nil, cerrdefs.IsNotFound(err):
+			return true, nil
+		case cerrdefs.IsConflict(err):
+			conflict = err
+			return false, nil
+		default:
+			return false, err
+		}
+	})
+
+	var timedOut *WaitTimeoutError
+	if errors.As(err, &timedOut) {
+		return fmt.Errorf("%w retrying conflicting removal: %w", err, conflict)
+	}
+
+	return err
+}
+
+// captureContainerLogs reads the last n lines of a container's stdout and
+// stderr, parsed the same way as the docker_logs data source.
+func captureContainerLogs(ctx context.Context, dockerClient *client.Client, id string, n int) ([]logLine, error) {
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       strconv.Itoa(n),
+	}
+
+	logs, err := dockerClient.ContainerLogs(ctx, id, options)
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	return readLogLines(ctx, logs, options, DefaultMaxLineBytes)
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+// removeHandler serves DELETE requests for container web, answering with the
+// given statuses in turn and repeating the last one.
+func removeHandler(t *testing.T, calls *atomic.Int32, statuses ...int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v1.44/containers/web" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			writeDaemonError(w, http.StatusNotImplemented, "unexpected request")
+			return
+		}
+		if r.URL.Query().Get("force") != "1" {
+			t.Errorf("expected a forced removal, got: %s", r.URL.RawQuery)
+		}
+
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+
+		switch status {
+		case http.StatusNoContent:
+			w.WriteHeader(status)
+		case http.StatusConflict:
+			writeDaemonError(w, status, "removal of container web is already in progress")
+		default:
+			writeDaemonError(w, status, http.StatusText(status))
+		}
+	})
+}
+
+func TestRemoveContainerRetriesConflict(t *testing.T) {
+	var calls atomic.Int32
+	dockerClient := newTestClient(t, removeHandler(t, &calls, http.StatusConflict, http.StatusNoContent))
+
+	if err := removeContainer(context.Background(), dockerClient, "web", 10*time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 removal attempts, got %d", got)
+	}
+}
+
+func TestRemoveContainerGivesUpOnTimeout(t *testing.T) {
+	var calls atomic.Int32
+	dockerClient := newTestClient(t, removeHandler(t, &calls, http.StatusConflict))
+
+	err := removeContainer(context.Background(), dockerClient, "web", 100*time.Millisecond)
+
+	var timedOut *WaitTimeoutError
+	if !errors.As(err, &timedOut) {
+		t.Fatalf("expected a WaitTimeoutError, got: %v", err)
+	}
+	if !cerrdefs.IsConflict(err) {
+		t.Errorf("expected the last conflict to be wrapped, got: %v", err)
+	}
+	if got := calls.Load(); got < 2 {
+		t.Errorf("expected the removal to be retried at the timeout, got %d attempts", got)
+	}
+}
+
+func TestRemoveContainerStatuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "removed", status: http.StatusNoContent},
+		{name: "already gone", status: http.StatusNotFound},
+		{name: "daemon error", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			dockerClient := newTestClient(t, removeHandler(t, &calls, tt.status))
+
+			err := removeContainer(context.Background(), dockerClient, "web", 10*time.Second)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("expected error %t, got: %v", tt.wantErr, err)
+			}
+			if got := calls.Load(); got != 1 {
+				t.Errorf("expected a single removal attempt, got %d", got)
+			}
+		})
+	}
+}
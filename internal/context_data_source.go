@@ -0,0 +1,224 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultContextName is the name of the docker CLI context built from
+// DOCKER_HOST, which is never written to the context store
+const DefaultContextName = "default"
+
+// contextAttrTypes are the attribute types of a docker_context context
+var contextAttrTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"host":        types.StringType,
+	"description": types.StringType,
+	"current":     types.BoolType,
+}
+
+type ContextDataSource struct{}
+
+type ContextDataSourceModel struct {
+	ConfigDir types.String `tfsdk:"config_dir"`
+	Contexts  types.List   `tfsdk:"contexts"`
+}
+
+// contextMetadata is the context-level metadata the docker CLI stores
+// alongside each context's endpoints
+type contextMetadata struct {
+	Description string
+}
+
+func NewContextDataSource() datasource.DataSource {
+	return &ContextDataSource{}
+}
+
+func (d *ContextDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_context"
+}
+
+func (d *ContextDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			List the docker CLI contexts in the local context store, as shown by
+			docker context ls.
+
+			Only the store on the machine running Terraform is read; no daemon is
+			contacted.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Optional
+
+			"config_dir": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The docker CLI configuration directory holding config.json and the
+					contexts store
+
+					Default: DOCKER_CONFIG, or ~/.docker when unset
+				`,
+			},
+
+			// Computed
+
+			"contexts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The contexts, sorted by name, starting with the default context built from DOCKER_HOST",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The context name",
+						},
+						"host": schema.StringAttribute{
+							Computed:    true,
+							Description: "The Docker daemon address of the context, e.g. unix:///var/run/docker.sock",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "The context description",
+						},
+						"current": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the docker CLI uses this context, from DOCKER_HOST, DOCKER_CONTEXT or config.json",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ContextDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ContextDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configDir := data.ConfigDir.ValueString()
+	if configDir == "" {
+		configDir = os.Getenv("DOCKER_CONFIG")
+	}
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Find Docker Config Directory",
+				fmt.Sprintf("config_dir and DOCKER_CONFIG are unset and the home directory is unknown: %v", err),
+			)
+			return
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+
+	current, err := currentContextName(configDir)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Docker Config",
+			formatError("read", fmt.Sprintf("%q", filepath.Join(configDir, "config.json")), "", "", err),
+		)
+		return
+	}
+
+	contextStore := store.New(filepath.Join(configDir, "contexts"), store.NewConfig(
+		func() any { return &contextMetadata{} },
+		store.EndpointTypeGetter(docker.DockerEndpoint, func() any { return &docker.EndpointMeta{} }),
+	))
+
+	stored, err := contextStore.List()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to List Docker Contexts",
+			formatError("list", fmt.Sprintf("contexts in %q", filepath.Join(configDir, "contexts")), "", "", err),
+		)
+		return
+	}
+
+	defaultHost := os.Getenv(client.EnvOverrideHost)
+	if defaultHost == "" {
+		defaultHost = client.DefaultDockerHost
+	}
+
+	contexts := []attr.Value{
+		types.ObjectValueMust(contextAttrTypes, map[string]attr.Value{
+			"name":        types.StringValue(DefaultContextName),
+			"host":        types.StringValue(defaultHost),
+			"description": types.StringValue("Current DOCKER_HOST based configuration"),
+			"current":     types.BoolValue(current == DefaultContextName),
+		}),
+	}
+
+	for _, meta := range stored {
+		description := ""
+		if m, ok := meta.Metadata.(contextMetadata); ok {
+			description = m.Description
+		}
+
+		host := ""
+		if endpoint, ok := meta.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta); ok {
+			host = endpoint.Host
+		}
+
+		contexts = append(contexts, types.ObjectValueMust(contextAttrTypes, map[string]attr.Value{
+			"name":        types.StringValue(meta.Name),
+			"host":        types.StringValue(host),
+			"description": types.StringValue(description),
+			"current":     types.BoolValue(meta.Name == current),
+		}))
+	}
+
+	data.Contexts = types.ListValueMust(types.ObjectType{AttrTypes: contextAttrTypes}, contexts)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// currentContextName returns the context the docker CLI would use, following
+// its precedence: DOCKER_HOST selects the default context, then DOCKER_CONTEXT,
+// then the currentContext of config.json in configDir.
+func currentContextName(configDir string) (string, error) {
+	if os.Getenv(client.EnvOverrideHost) != "" {
+		return DefaultContextName, nil
+	}
+
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultContextName, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return "", err
+	}
+
+	if config.CurrentContext == "" {
+		return DefaultContextName, nil
+	}
+
+	return config.CurrentContext, nil
+}
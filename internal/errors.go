@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+// NotFoundError reports that a container, path or image does not exist on the
+// daemon. It wraps the daemon's error.
+type NotFoundError struct {
+	Resource string // the kind of object looked up, e.g. "container"
+	Name     string // the name it was looked up by
+	Err      error
+}
+
+func (e *NotFoundError) Error() string {
+	return formatError("find", fmt.Sprintf("%s %q", e.Resource, e.Name), "", "it does not exist on the daemon", nil)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports a configured value that was rejected before
+// talking to the daemon, such as a malformed container name or path.
+type ValidationError struct {
+	Field  string // the kind of value validated, e.g. "container name"
+	Reason string // why the value was rejected, including the value itself
+}
+
+func (e *ValidationError) Error() string {
+	return formatError("validate", e.Field, "", e.Reason, nil)
+}
+
+// TarError reports a failure reading an archive stream from the daemon,
+// either for the stream as a whole or for a single entry.
+type TarError struct {
+	Entry string // the name of the entry, empty for errors about the whole stream
+	Err   error
+}
+
+func (e *TarError) Error() string {
+	if e.Entry == "" {
+		return formatError("read", "tar stream", "", "", e.Err)
+	}
+	return formatError("read", fmt.Sprintf("tar entry %q", e.Entry), "", "", e.Err)
+}
+
+func (e *TarError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseTooLargeError reports a daemon response body larger than the
+// provider's max_response_bytes.
+type ResponseTooLargeError struct {
+	Limit int64 // the max_response_bytes the response exceeded
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return formatError("read", "daemon response", "", fmt.Sprintf("it exceeds max_response_bytes of %d bytes", e.Limit), nil)
+}
+
+// WaitTimeoutError reports a wait condition that was still unmet when its
+// timeout passed.
+type WaitTimeoutError struct {
+	Timeout time.Duration // how long the condition was polled for
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s", e.Timeout)
+}
+
+// wrapNotFound returns a NotFoundError for err when the daemon reported the
+// named resource missing, and err unchanged otherwise.
+func wrapNotFound(resource, name string, err error) error {
+	if err == nil || !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	return &NotFoundError{Resource: resource, Name: name, Err: err}
+}
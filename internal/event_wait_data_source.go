@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultEventWaitTimeout is the number of seconds waited for an event when timeout is unset
+const DefaultEventWaitTimeout = 60
+
+type EventWaitDataSource struct {
+	DockerClient *client.Client
+	Limiter      *RequestLimiter
+}
+
+type EventWaitDataSourceModel struct {
+	Type      types.String `tfsdk:"type"`
+	Action    types.String `tfsdk:"action"`
+	Container types.String `tfsdk:"container"`
+	Since     types.String `tfsdk:"since"`
+	Timeout   types.Int32  `tfsdk:"timeout"`
+	Event     types.Object `tfsdk:"event"`
+}
+
+func NewEventWaitDataSource() datasource.DataSource {
+	return &EventWaitDataSource{}
+}
+
+func (d *EventWaitDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_event_wait"
+}
+
+func (d *EventWaitDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Wait for a daemon event, such as a container becoming healthy, and
+			return the first one matching.
+
+			The read fails once timeout has passed without a match, so it never
+			blocks a plan indefinitely.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"action": schema.StringAttribute{
+				Required:    true,
+				Description: "The event action to wait for, e.g. start, die or \"health_status: healthy\"",
+			},
+
+			// Optional
+
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Description: "The object type the event must be about (container, image, network, ...)",
+			},
+
+			"container": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name or ID of the container the event must be about",
+			},
+
+			"since": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Also match events created since this timestamp (unix or RFC3339) or
+					relative duration (e.g. 5m), so an event that happened just before
+					the read is not missed
+
+					Default: the time of the read
+				`,
+			},
+
+			"timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds to wait for a matching event
+
+					Default: 60 seconds
+				`,
+			},
+
+			// Computed
+
+			"event": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The first matching event",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Computed:    true,
+						Description: "The object type (container, image, network, ...)",
+					},
+					"action": schema.StringAttribute{
+						Computed:    true,
+						Description: "The event action (start, stop, pull, ...)",
+					},
+					"actor_id": schema.StringAttribute{
+						Computed:    true,
+						Description: "The ID of the object the event is about",
+					},
+					"actor_attributes": schema.MapAttribute{
+						Computed:    true,
+						Description: "Attributes of the object the event is about",
+						ElementType: types.StringType,
+					},
+					"time": schema.StringAttribute{
+						Computed:    true,
+						Description: "The event time",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *EventWaitDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.Limiter = config.Limiter
+}
+
+func (d *EventWaitDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EventWaitDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Action.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Event Action",
+			"action cannot be empty",
+		)
+		return
+	}
+
+	if !data.Container.IsNull() {
+		if err := validateContainerName(data.Container.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Container Name",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	timeout := int32(DefaultEventWaitTimeout)
+	if !data.Timeout.IsNull() {
+		timeout = data.Timeout.ValueInt32()
+	}
+
+	if timeout < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Timeout",
+			fmt.Sprintf("timeout must be at least 1, got: %d", timeout),
+		)
+		return
+	}
+
+	// The deadline bounds waiting for a request slot as well as the stream,
+	// and until makes the daemon end the stream even if the context is lost.
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	eventFilters := filters.NewArgs(filters.Arg("event", data.Action.ValueString()))
+	if !data.Type.IsNull() {
+		eventFilters.Add("type", data.Type.ValueString())
+	}
+	if !data.Container.IsNull() {
+		eventFilters.Add("container", data.Container.ValueString())
+	}
+
+	msg, err := waitForEvent(ctx, d.DockerClient, events.ListOptions{
+		Since:   data.Since.ValueString(),
+		Until:   strconv.FormatInt(deadline.Unix()+1, 10),
+		Filters: eventFilters,
+	})
+	if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+		resp.Diagnostics.AddError(
+			"Event Wait Timed Out",
+			formatError("wait for", fmt.Sprintf("%q event", data.Action.ValueString()), data.Container.ValueString(), fmt.Sprintf("none was received within the %d second timeout", timeout), nil),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Daemon Events",
+			formatError("wait for", fmt.Sprintf("%q event", data.Action.ValueString()), data.Container.ValueString(), "", err),
+		)
+		return
+	}
+
+	data.Event = eventObjectValue(msg)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// waitForEvent returns the first event the daemon sends for options. It
+// returns io.EOF when the stream ends at options.Until without one.
+func waitForEvent(ctx context.Context, dockerClient *client.Client, options events.ListOptions) (events.Message, error) {
+	// cancelling closes the event stream once the event has been received
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errs := dockerClient.Events(ctx, options)
+
+	select {
+	case msg := <-messages:
+		return msg, nil
+	case err := <-errs:
+		return events.Message{}, err
+	}
+}
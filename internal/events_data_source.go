@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultMaxEvents is the number of events collected when max_events is unset
+const DefaultMaxEvents = 100
+
+type EventsDataSource struct {
+	DockerClient *client.Client
+	Limiter      *RequestLimiter
+}
+
+type EventsDataSourceModel struct {
+	Since     types.String `tfsdk:"since"`
+	Until     types.String `tfsdk:"until"`
+	MaxEvents types.Int32  `tfsdk:"max_events"`
+	Events    types.List   `tfsdk:"events"`
+}
+
+// eventAttrTypes are the attribute types of a single daemon event
+var eventAttrTypes = map[string]attr.Type{
+	"type":             types.StringType,
+	"action":           types.StringType,
+	"actor_id":         types.StringType,
+	"actor_attributes": types.MapType{ElemType: types.StringType},
+	"time":             types.StringType,
+}
+
+func NewEventsDataSource() datasource.DataSource {
+	return &EventsDataSource{}
+}
+
+func (d *EventsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_events"
+}
+
+func (d *EventsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Retrieve daemon events (container start/stop, image pull, ...) from a
+			bounded time window.
+
+			Both since and until are required so the read always terminates.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"since": schema.StringAttribute{
+				Required:    true,
+				Description: "Show events created since this timestamp (unix or RFC3339) or relative duration (e.g. 10m)",
+			},
+
+			"until": schema.StringAttribute{
+				Required:    true,
+				Description: "Show events created until this timestamp (unix or RFC3339) or relative duration (e.g. 0s)",
+			},
+
+			// Optional
+
+			"max_events": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum number of events to collect
+
+					Default: 100
+				`,
+			},
+
+			// Computed
+
+			"events": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The events in the window, oldest first",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The object type (container, image, network, ...)",
+						},
+						"action": schema.StringAttribute{
+							Computed:    true,
+							Description: "The event action (start, stop, pull, ...)",
+						},
+						"actor_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the object the event is about",
+						},
+						"actor_attributes": schema.MapAttribute{
+							Computed:    true,
+							Description: "Attributes of the object the event is about",
+							ElementType: types.StringType,
+						},
+						"time": schema.StringAttribute{
+							Computed:    true,
+							Description: "The event time",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *EventsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.Limiter = config.Limiter
+}
+
+func (d *EventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EventsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Since.ValueString() == "" || data.Until.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Event Window",
+			"Both since and until must be set to bound the events read",
+		)
+		return
+	}
+
+	maxEvents := int32(DefaultMaxEvents)
+	if !data.MaxEvents.IsNull() {
+		maxEvents = data.MaxEvents.ValueInt32()
+	}
+
+	if maxEvents < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Max Events",
+			fmt.Sprintf("max_events must be at least 1, got: %d", maxEvents),
+		)
+		return
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	// cancelling stops the event stream once max_events have been collected
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errs := d.DockerClient.Events(ctx, events.ListOptions{
+		Since: data.Since.ValueString(),
+		Until: data.Until.ValueString(),
+	})
+
+	eventValues := []attr.Value{}
+
+collect:
+	for len(eventValues) < int(maxEvents) {
+		select {
+		case msg := <-messages:
+			eventValues = append(eventValues, eventObjectValue(msg))
+
+		case err := <-errs:
+			// the stream ends with io.EOF once until has passed
+			if err == io.EOF {
+				break collect
+			}
+
+			resp.Diagnostics.AddError(
+				"Unable to Read Daemon Events",
+				fmt.Sprintf("Error reading events between %q and %q: %v", data.Since.ValueString(), data.Until.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	data.Events = types.ListValueMust(
+		types.ObjectType{AttrTypes: eventAttrTypes},
+		eventValues,
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// eventObjectValue converts a daemon event to an object of eventAttrTypes.
+func eventObjectValue(msg events.Message) types.Object {
+	attributes := map[string]attr.Value{}
+	for key, value := range msg.Actor.Attributes {
+		attributes[key] = types.StringValue(value)
+	}
+
+	return types.ObjectValueMust(
+		eventAttrTypes,
+		map[string]attr.Value{
+			"type":             types.StringValue(string(msg.Type)),
+			"action":           types.StringValue(string(msg.Action)),
+			"actor_id":         types.StringValue(msg.Actor.ID),
+			"actor_attributes": types.MapValueMust(types.StringType, attributes),
+			"time":             types.StringValue(time.Unix(0, msg.TimeNano).UTC().Format(time.RFC3339Nano)),
+		},
+	)
+}
@@ -2,10 +2,26 @@ package internal
 
 import (
 	"archive/tar"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -13,15 +29,66 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// envReference matches ${VAR} and $VAR references to environment variables
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// parentStatAttrTypes extend statAttrTypes with the owner of the directory,
+// which is only known from its tar header
+var parentStatAttrTypes = func() map[string]attr.Type {
+	attrTypes := maps.Clone(statAttrTypes)
+	attrTypes["uid"] = types.Int32Type
+	attrTypes["gid"] = types.Int32Type
+	return attrTypes
+}()
+
 type FileDataSource struct {
 	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+	DaemonOS     string
+
+	MaxResponseBytes int64
 }
 
 type FileDataSourceModel struct {
 	Container types.String `tfsdk:"container"`
+	Label     types.Map    `tfsdk:"label"`
+	Host      types.String `tfsdk:"host"`
+	Timeout   types.Int32  `tfsdk:"timeout"`
 	Path      types.String `tfsdk:"path"`
 	File      types.Object `tfsdk:"file"`
 	Stat      types.Object `tfsdk:"stat"`
+
+	ResolvedPath types.String `tfsdk:"resolved_path"`
+
+	SkipExistenceCheck types.Bool `tfsdk:"skip_existence_check"`
+
+	WaitForPath types.Bool  `tfsdk:"wait_for_path"`
+	WaitTimeout types.Int32 `tfsdk:"wait_timeout"`
+	Snapshot    types.Bool  `tfsdk:"snapshot"`
+
+	LineEnding   types.String `tfsdk:"line_ending"`
+	ContentLines types.List   `tfsdk:"content_lines"`
+
+	Dereference types.Bool `tfsdk:"dereference"`
+
+	IncludeParentStat types.Bool   `tfsdk:"include_parent_stat"`
+	ParentStat        types.Object `tfsdk:"parent_stat"`
+
+	ReadBindMountsFromHost types.Bool   `tfsdk:"read_bind_mounts_from_host"`
+	HostPath               types.String `tfsdk:"host_path"`
+
+	ExpectedSHA256 types.String `tfsdk:"expected_sha256"`
+
+	NormalizeLineEndings types.Bool `tfsdk:"normalize_line_endings"`
+	TrimTrailingNewline  types.Bool `tfsdk:"trim_trailing_newline"`
+
+	InterpolateEnv      types.Bool   `tfsdk:"interpolate_env"`
+	ContentInterpolated types.String `tfsdk:"content_interpolated"`
+
+	CompareWithImage  types.Bool   `tfsdk:"compare_with_image"`
+	ModifiedAtRuntime types.Bool   `tfsdk:"modified_at_runtime"`
+	ImageContent      types.String `tfsdk:"image_content"`
 }
 
 func NewFileDataSource() datasource.DataSource {
@@ -43,18 +110,199 @@ func (d *FileDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 
 			// Required
 
+			"path": schema.StringAttribute{
+				Required: true,
+				MarkdownDescription: `
+					The filepath to request from the container. When the provider's
+					daemon runs Windows containers, paths may use a drive letter and
+					either separator.
+
+					Otherwise the last element may be a glob (e.g. /etc/app/*.conf) that
+					must match exactly one file in its directory. A path that exists as
+					written, like /app/pages/[id].js, is read as it is, and paths are
+					never matched as globs with wait_for_path. Matching copies the whole
+					directory from the daemon, file content included, though the content
+					is discarded, so a glob in a large directory is slow and counts
+					towards max_response_bytes.
+				`,
+			},
+
+			// Optional
+
 			"container": schema.StringAttribute{
-				Required:    true,
-				Description: "The name of the container",
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the container, resolved from label when not set",
 			},
 
-			"path": schema.StringAttribute{
-				Required:    true,
-				Description: "The filepath to request from the container",
+			"label": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels identifying exactly one container, as an alternative to container (e.g. compose service labels)",
+				ElementType: types.StringType,
+			},
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds the whole read may take, including waiting for a free request
+					slot, on top of the provider's per-request timeout. Unset means no
+					limit.
+				`,
+			},
+
+			"skip_existence_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip inspecting the container up front, saving a request at the cost of a less specific error when it is missing",
+			},
+
+			"wait_for_path": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to retry until the container and path exist, e.g. while an entrypoint is still writing the file",
+			},
+
+			"wait_timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds to wait for the path when wait_for_path is set
+
+					Default: 60 seconds
+				`,
+			},
+
+			"snapshot": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to read from a point-in-time snapshot of the container, so
+					concurrent writes cannot produce a torn read. The container is
+					briefly paused and committed to a temporary image, which is removed
+					after the read. Volumes are not part of the snapshot.
+
+					Cannot be combined with wait_for_path.
+				`,
+			},
+
+			"line_ending": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The line ending content_lines is split on
+
+					Default: "\n"
+				`,
+			},
+
+			"dereference": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether stat describes the target of path when it is a symlink,
+					rather than the symlink itself, at the cost of an extra request.
+					The target is the fully resolved link_target the daemon reports.
+					Content is read from path either way.
+
+					Default: false
+				`,
+			},
+
+			"include_parent_stat": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to also stat the directory containing the file into parent_stat, at the cost of an extra request",
+			},
+
+			"read_bind_mounts_from_host": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to read the file directly from the host when path falls
+					under a bind mount of the container, skipping the copy through the
+					daemon. Only used when the daemon is reached over a unix socket, so
+					the provider runs on the same host, and when the host file is a
+					regular file reached without symlinks; otherwise the file is read
+					through the daemon. Extended attributes are not read from the host.
+
+					Cannot be combined with snapshot.
+				`,
+			},
+
+			"normalize_line_endings": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to convert CRLF line endings in content to LF, so files edited on Windows don't produce diffs in rendered templates",
+			},
+
+			"trim_trailing_newline": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to remove a single final line ending (\"\\n\" or \"\\r\\n\") from content, e.g. before interpolating it into a heredoc",
+			},
+
+			"interpolate_env": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to substitute ${VAR} and $VAR references in the content with
+					the container's environment variables into content_interpolated, at
+					the cost of an extra request. References to variables the container
+					doesn't set are left as they are.
+				`,
+			},
+
+			"compare_with_image": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to also read the file as it is in the container's image into
+					image_content, and report in modified_at_runtime whether the
+					container has since changed it. The image's file is read through a
+					temporary, never started container of the image, which is removed
+					after the read. Files in volumes and bind mounts are not part of the
+					image and never count as modified.
+				`,
+			},
+
+			"expected_sha256": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The hex-encoded SHA-256 checksum the file content must have. The
+					read fails when the content differs, e.g. to assert a config file
+					has not been modified.
+				`,
 			},
 
 			// Computed
 
+			"resolved_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The path of the file read, with a glob in path resolved to the matching file",
+			},
+
+			"host_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The host path the file was read from; null unless read_bind_mounts_from_host is set and the file was read from the host",
+			},
+
+			"content_interpolated": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The file content with environment variable references substituted; null unless interpolate_env is set",
+			},
+
+			"modified_at_runtime": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the container's writable layer added or changed the file, including its metadata, since it was created from its image; null unless compare_with_image is set",
+			},
+
+			"image_content": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The file content as it is in the container's image; null unless compare_with_image is set, or when the image has no such file",
+			},
+
+			"content_lines": schema.ListAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The file content split into lines, without a trailing empty line for a final line ending",
+				ElementType: types.StringType,
+			},
+
 			"file": schema.SingleNestedAttribute{
 				Computed:    true,
 				Description: "The first file returned",
@@ -64,6 +312,11 @@ func (d *FileDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 						Sensitive:   true,
 						Description: "The file content",
 					},
+					"raw_content": schema.StringAttribute{
+						Computed:    true,
+						Sensitive:   true,
+						Description: "The file content as read, before normalize_line_endings and trim_trailing_newline; null unless either is set",
+					},
 					"mod_time": schema.StringAttribute{
 						Computed:    true,
 						Description: "The file modification time",
@@ -88,34 +341,35 @@ func (d *FileDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 						Computed:    true,
 						Description: "The file owner GID",
 					},
+					"xattrs": schema.MapAttribute{
+						Computed:    true,
+						Description: "The file's extended attributes (e.g. security.selinux), keyed by name",
+						ElementType: types.StringType,
+					},
 				},
 			},
 
 			"stat": schema.SingleNestedAttribute{
 				Computed:    true,
 				Description: "Stat for file path",
-				Attributes: map[string]schema.Attribute{
-					"name": schema.StringAttribute{
-						Computed:    true,
-						Description: "The file name",
-					},
-					"size": schema.Int64Attribute{
-						Computed:    true,
-						Description: "The file size",
-					},
-					"mode": schema.Int32Attribute{
-						Computed:    true,
-						Description: "The file mode",
-					},
-					"mtime": schema.StringAttribute{
+				Attributes:  statSchemaAttributes(),
+			},
+
+			"parent_stat": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Stat for the directory containing the file, including its owner; null unless include_parent_stat is set",
+				Attributes: func() map[string]schema.Attribute {
+					attributes := statSchemaAttributes()
+					attributes["uid"] = schema.Int32Attribute{
 						Computed:    true,
-						Description: "The file modification time",
-					},
-					"link_target": schema.StringAttribute{
+						Description: "The directory owner UID",
+					}
+					attributes["gid"] = schema.Int32Attribute{
 						Computed:    true,
-						Description: "The file link target",
-					},
-				},
+						Description: "The directory owner GID",
+					}
+					return attributes
+				}(),
 			},
 		},
 	}
@@ -137,6 +391,10 @@ func (d *FileDataSource) Configure(ctx context.Context, req datasource.Configure
 	}
 
 	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+	d.DaemonOS = config.DaemonOS
+	d.MaxResponseBytes = config.MaxResponseBytes
 }
 
 func (d *FileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -148,30 +406,264 @@ func (d *FileDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	// Validate container name
-	if err := validateContainerName(data.Container.ValueString()); err != nil {
+	ref := ContainerRef{Name: data.Container.ValueString()}
+	resp.Diagnostics.Append(data.Label.ElementsAs(ctx, &ref.Labels, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := ref.Validate(); err != nil {
 		resp.Diagnostics.AddError(
-			"Invalid Container Name",
-			fmt.Sprintf("Container name validation failed: %v", err),
+			"Invalid Container Selector",
+			err.Error(),
 		)
 		return
 	}
 
 	// Validate and sanitize path
-	sanitizedPath, err := sanitizePath(data.Path.ValueString())
+	sanitizedPath, err := sanitizePath(data.Path.ValueString(), d.DaemonOS)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Invalid File Path",
-			fmt.Sprintf("Path validation failed for %q: %v", data.Path.ValueString(), err),
+			err.Error(),
 		)
 		return
 	}
 
-	file, stat, err := d.DockerClient.CopyFromContainer(ctx, data.Container.ValueString(), sanitizedPath)
+	// paths waited for are taken as written, as a glob can't match a path
+	// that doesn't exist yet
+	isGlob := d.DaemonOS != DaemonOSWindows && !data.WaitForPath.ValueBool() && strings.ContainsAny(sanitizedPath, "*?[")
+
+	if data.Snapshot.ValueBool() && data.WaitForPath.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Conflicting File Read Options",
+			"snapshot and wait_for_path cannot be combined: a snapshot never gains paths written after it was taken",
+		)
+		return
+	}
+
+	if data.Snapshot.ValueBool() && data.ReadBindMountsFromHost.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Conflicting File Read Options",
+			"snapshot and read_bind_mounts_from_host cannot be combined: bind mounts are not part of a snapshot",
+		)
+		return
+	}
+
+	lineEnding := "\n"
+	if !data.LineEnding.IsNull() {
+		lineEnding = data.LineEnding.ValueString()
+	}
+
+	if lineEnding == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Line Ending",
+			"line_ending cannot be empty",
+		)
+		return
+	}
+
+	expectedSHA256 := strings.ToLower(data.ExpectedSHA256.ValueString())
+	if !data.ExpectedSHA256.IsNull() {
+		if _, err := hex.DecodeString(expectedSHA256); err != nil || len(expectedSHA256) != hex.EncodedLen(sha256.Size) {
+			resp.Diagnostics.AddError(
+				"Invalid Expected SHA-256",
+				fmt.Sprintf("expected_sha256 must be %d hex characters, got: %q", hex.EncodedLen(sha256.Size), data.ExpectedSHA256.ValueString()),
+			)
+			return
+		}
+	}
+
+	if !data.Timeout.IsNull() {
+		if data.Timeout.ValueInt32() < 1 {
+			resp.Diagnostics.AddError(
+				"Invalid Timeout",
+				fmt.Sprintf("timeout must be at least 1, got: %d", data.Timeout.ValueInt32()),
+			)
+			return
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(data.Timeout.ValueInt32())*time.Second)
+		defer cancel()
+
+		// added alongside the error of whichever request was cut short
+		defer func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				resp.Diagnostics.AddError(
+					"Read Timed Out",
+					fmt.Sprintf("docker_file did not finish reading from container %q within the %d second timeout", data.Container.ValueString(), data.Timeout.ValueInt32()),
+				)
+			}
+		}()
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	name, err := ResolveContainer(ctx, dockerClient, ref)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Container",
+			formatError("find", fmt.Sprintf("a container with labels %v", ref.Labels), "", "", err),
+		)
+		return
+	}
+
+	data.Container = types.StringValue(name)
+
+	// wait_for_path also waits for the container to be created, and containers
+	// resolved from labels are already known to exist
+	if !data.SkipExistenceCheck.ValueBool() && !data.WaitForPath.ValueBool() && data.Label.IsNull() {
+		err := checkContainerExists(ctx, dockerClient, data.Container.ValueString())
+
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Container Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+	}
+
+	readContainer := data.Container.ValueString()
+
+	if data.Snapshot.ValueBool() {
+		snapshotID, cleanup, err := snapshotContainer(ctx, dockerClient, data.Container.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Snapshot Container",
+				formatError("snapshot", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+		defer func() {
+			if cleanupErr := cleanup(); cleanupErr != nil {
+				resp.Diagnostics.AddWarning(
+					"Resource Cleanup Warning",
+					formatError("remove", fmt.Sprintf("snapshot of container %q", data.Container.ValueString()), "", "", cleanupErr),
+				)
+			}
+		}()
+		readContainer = snapshotID
+	}
+
+	if isGlob {
+		sanitizedPath, err = resolvePathGlob(ctx, dockerClient, readContainer, sanitizedPath, d.MaxResponseBytes)
+
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			resp.Diagnostics.AddError(
+				"Invalid File Path",
+				err.Error(),
+			)
+			return
+		}
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"File Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Resolve File Path",
+				formatError("resolve", fmt.Sprintf("path %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+	}
+
+	if d.DaemonOS == DaemonOSWindows {
+		data.ResolvedPath = types.StringValue(sanitizedPath)
+	} else {
+		data.ResolvedPath = types.StringValue("/" + sanitizedPath)
+	}
+
+	var file io.ReadCloser
+	var stat container.PathStat
+
+	data.HostPath = types.StringNull()
+
+	if data.ReadBindMountsFromHost.ValueBool() && isLocalDaemon(dockerClient) {
+		inspect, err := dockerClient.ContainerInspect(ctx, readContainer)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q for its bind mounts", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+
+		// fall back to the daemon when the file can't be read from the host
+		if hostPath, ok := hostPathForBindMount(inspect.Mounts, "/"+sanitizedPath); ok {
+			if file, stat, err = openHostFile(hostPath); err == nil {
+				data.HostPath = types.StringValue(hostPath)
+			}
+		}
+	}
+
+	switch {
+	case !data.HostPath.IsNull():
+		// already opened from the host
+	case data.WaitForPath.ValueBool():
+		timeout := int32(60)
+		if !data.WaitTimeout.IsNull() {
+			timeout = data.WaitTimeout.ValueInt32()
+		}
+		file, stat, err = waitForCopyFromContainer(ctx, dockerClient, data.Container.ValueString(), sanitizedPath, time.Duration(timeout)*time.Second)
+	default:
+		file, stat, err = dockerClient.CopyFromContainer(ctx, readContainer, sanitizedPath)
+		err = wrapNotFound("container path", data.Container.ValueString()+":"+data.Path.ValueString(), err)
+	}
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		resp.Diagnostics.AddError(
+			"File Not Found",
+			err.Error(),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read File from Container",
-			fmt.Sprintf("Error reading file %q from container %q: %v", data.Path.ValueString(), data.Container.ValueString(), err),
+			formatError("read", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
 		)
 		return
 	}
@@ -179,89 +671,609 @@ func (d *FileDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		if closeErr := file.Close(); closeErr != nil {
 			resp.Diagnostics.AddWarning(
 				"Resource Cleanup Warning",
-				fmt.Sprintf("Failed to close file stream for %q from container %q: %v", data.Path.ValueString(), data.Container.ValueString(), closeErr),
+				formatError("close", fmt.Sprintf("file stream for %q", data.Path.ValueString()), data.Container.ValueString(), "", closeErr),
 			)
 		}
 	}()
 
-	data.Stat = types.ObjectValueMust(
-		map[string]attr.Type{
-			"name":        types.StringType,
-			"size":        types.Int64Type,
-			"mode":        types.Int32Type,
-			"mtime":       types.StringType,
-			"link_target": types.StringType,
-		},
+	data.Stat = statObjectValue(stat)
 
-		map[string]attr.Value{
-			"name":        types.StringValue(stat.Name),
-			"size":        types.Int64Value(stat.Size),
-			"mode":        types.Int32Value(int32(stat.Mode)),
-			"mtime":       types.StringValue(stat.Mtime.Format(time.RFC3339)),
-			"link_target": types.StringValue(stat.LinkTarget),
-		},
-	)
+	if data.Dereference.ValueBool() {
+		targetStat, err := dereferenceStat(ctx, dockerClient, readContainer, stat)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Stat Symlink Target",
+				formatError("stat", fmt.Sprintf("target %q of symlink %q", stat.LinkTarget, data.Path.ValueString()), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+
+		data.Stat = statObjectValue(targetStat)
+	}
+
+	if stat.Mode.IsDir() {
+		resp.Diagnostics.AddError(
+			"Path Is a Directory, use docker_files",
+			formatError("read", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(), "it is a directory; use the docker_files data source to read its contents", nil),
+		)
+		return
+	}
+
+	data.ParentStat = types.ObjectNull(parentStatAttrTypes)
 
-	tr := tar.NewReader(file)
-	allFiles, err := extractAllFilesFromTar(tr)
+	if data.IncludeParentStat.ValueBool() {
+		parentPath := containerParentDir(sanitizedPath, d.DaemonOS)
+
+		parentStat, parentHeader, err := statDirectory(ctx, dockerClient, readContainer, parentPath)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Stat Parent Directory",
+				formatError("read", fmt.Sprintf("directory %q", parentPath), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+
+		attributes := statObjectValue(parentStat).Attributes()
+		attributes["uid"] = types.Int32Value(int32(parentHeader.Uid))
+		attributes["gid"] = types.Int32Value(int32(parentHeader.Gid))
+
+		data.ParentStat = types.ObjectValueMust(parentStatAttrTypes, attributes)
+	}
+
+	tr, err := newTarReader(newLimitedReader(file, d.MaxResponseBytes))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Extract Files from Tar",
-			fmt.Sprintf("Error extracting files from tar stream for %q: %v", data.Path.ValueString(), err),
+			formatError("read", fmt.Sprintf("tar stream for %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
 		)
 		return
 	}
 
-	if len(allFiles) == 0 {
+	allFiles, err := extractAllFilesFromTar(tr, TarContentFull)
+
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
 		resp.Diagnostics.AddError(
-			"No Files Found in Tar",
-			fmt.Sprintf("No files were found in tar stream for %q", data.Path.ValueString()),
+			"Daemon Response Too Large",
+			formatError("read", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Extract Files from Tar",
+			formatError("extract", fmt.Sprintf("files from tar stream for %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
 		)
 		return
 	}
 
-	if len(allFiles) > 1 {
-		var fileNames []string
-		for name := range allFiles {
-			fileNames = append(fileNames, name)
-		}
+	if len(allFiles) == 0 {
 		resp.Diagnostics.AddError(
-			"Multiple Files Found in Tar",
-			fmt.Sprintf("Expected exactly one file in tar stream for %q, but found %d files: %v", 
-				data.Path.ValueString(), len(allFiles), fileNames),
+			"No Files Found in Tar",
+			formatError("read", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(), "the tar stream holds no files", nil),
 		)
 		return
 	}
 
-	// Get the single file
+	// The archive can hold parent directory entries alongside the file
+	// itself, so fall back to the single regular file when there is one.
 	var fileInfo *FileInfo
+	var regularFiles []*FileInfo
 	for _, info := range allFiles {
 		fileInfo = info
-		break
+		if info.Header.Typeflag == tar.TypeReg {
+			regularFiles = append(regularFiles, info)
+		}
+	}
+
+	if len(allFiles) > 1 {
+		if len(regularFiles) != 1 {
+			var fileNames []string
+			for name := range allFiles {
+				fileNames = append(fileNames, name)
+			}
+			resp.Diagnostics.AddError(
+				"Multiple Files Found in Tar",
+				formatError("read", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(),
+					fmt.Sprintf("expected exactly one file in the tar stream, but found %d files: %v", len(allFiles), fileNames), nil),
+			)
+			return
+		}
+		fileInfo = regularFiles[0]
+	}
+
+	if !data.ExpectedSHA256.IsNull() {
+		sum := sha256.Sum256(fileInfo.Content)
+		if actual := hex.EncodeToString(sum[:]); actual != expectedSHA256 {
+			resp.Diagnostics.AddError(
+				"File Checksum Mismatch",
+				formatError("verify", fmt.Sprintf("file %q", data.Path.ValueString()), data.Container.ValueString(),
+					fmt.Sprintf("expected SHA-256 %s, got %s", expectedSHA256, actual), nil),
+			)
+			return
+		}
+	}
+
+	content := normalizeContent(string(fileInfo.Content), data.NormalizeLineEndings.ValueBool(), data.TrimTrailingNewline.ValueBool())
+
+	rawContent := types.StringNull()
+	if data.NormalizeLineEndings.ValueBool() || data.TrimTrailingNewline.ValueBool() {
+		rawContent = types.StringValue(string(fileInfo.Content))
 	}
 
 	data.File = types.ObjectValueMust(
 		map[string]attr.Type{
-			"content":  types.StringType,
-			"gid":      types.Int32Type,
-			"mod_time": types.StringType,
-			"mode":     types.Int64Type,
-			"name":     types.StringType,
-			"size":     types.Int64Type,
-			"uid":      types.Int32Type,
+			"content":     types.StringType,
+			"raw_content": types.StringType,
+			"gid":         types.Int32Type,
+			"mod_time":    types.StringType,
+			"mode":        types.Int64Type,
+			"name":        types.StringType,
+			"size":        types.Int64Type,
+			"uid":         types.Int32Type,
+			"xattrs":      types.MapType{ElemType: types.StringType},
 		},
 
 		map[string]attr.Value{
-			"content":  types.StringValue(string(fileInfo.Content)),
-			"gid":      types.Int32Value(int32(fileInfo.Header.Gid)),
-			"mod_time": types.StringValue(fileInfo.Header.ModTime.Format(time.RFC3339)),
-			"mode":     types.Int64Value(fileInfo.Header.Mode),
-			"name":     types.StringValue(fileInfo.Header.Name),
-			"size":     types.Int64Value(fileInfo.Header.Size),
-			"uid":      types.Int32Value(int32(fileInfo.Header.Uid)),
+			"content":     types.StringValue(content),
+			"raw_content": rawContent,
+			"gid":         types.Int32Value(int32(fileInfo.Header.Gid)),
+			"mod_time":    types.StringValue(fileInfo.Header.ModTime.Format(time.RFC3339)),
+			"mode":        types.Int64Value(fileInfo.Header.Mode),
+			"name":        types.StringValue(fileInfo.Header.Name),
+			"size":        types.Int64Value(fileInfo.Header.Size),
+			"uid":         types.Int32Value(int32(fileInfo.Header.Uid)),
+			"xattrs":      xattrsMapValue(fileInfo.Header),
 		},
 	)
 
+	data.ContentInterpolated = types.StringNull()
+
+	if data.InterpolateEnv.ValueBool() {
+		inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q for its environment", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+
+		var env []string
+		if inspect.Config != nil {
+			env = inspect.Config.Env
+		}
+
+		data.ContentInterpolated = types.StringValue(interpolateEnv(content, env))
+	}
+
+	data.ModifiedAtRuntime = types.BoolNull()
+	data.ImageContent = types.StringNull()
+
+	if data.CompareWithImage.ValueBool() {
+		modified, err := pathChangedInContainer(ctx, dockerClient, data.Container.ValueString(), "/"+sanitizedPath)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Container Changes",
+				formatError("diff", fmt.Sprintf("container %q against its image", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+
+		inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q for its image", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+
+		// the image is read by ID, and named as configured when it is known
+		image := inspect.Image
+		if inspect.Config != nil && inspect.Config.Image != "" {
+			image = inspect.Config.Image
+		}
+
+		imageContent, found, err := readImageFile(ctx, dockerClient, inspect.Image, sanitizedPath, d.MaxResponseBytes)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read File from Image",
+				formatError("read", fmt.Sprintf("file %q from image %q", data.Path.ValueString(), image), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+
+		data.ModifiedAtRuntime = types.BoolValue(modified)
+		if found {
+			data.ImageContent = types.StringValue(string(imageContent))
+		}
+	}
+
+	lineValues := []attr.Value{}
+	for _, line := range splitLines(content, lineEnding) {
+		lineValues = append(lineValues, types.StringValue(line))
+	}
+
+	data.ContentLines = types.ListValueMust(types.StringType, lineValues)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// normalizeContent converts CRLF line endings to LF when normalizeLineEndings
+// is set, then removes a single final "\n" or "\r\n" when trimTrailingNewline
+// is set.
+func normalizeContent(content string, normalizeLineEndings, trimTrailingNewline bool) string {
+	if normalizeLineEndings {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+
+	if trimTrailingNewline {
+		content = strings.TrimSuffix(strings.TrimSuffix(content, "\n"), "\r")
+	}
+
+	return content
+}
+
+// interpolateEnv replaces ${VAR} and $VAR references in content with the
+// values of env, given in KEY=VALUE form. References to variables env doesn't
+// set are kept verbatim; variables set without a value expand to nothing.
+func interpolateEnv(content string, env []string) string {
+	values := map[string]string{}
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		values[name] = value
+	}
+
+	return envReference.ReplaceAllStringFunc(content, func(reference string) string {
+		match := envReference.FindStringSubmatch(reference)
+		name := cmp.Or(match[1], match[2])
+
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return reference
+	})
+}
+
+// splitLines splits content into lines on lineEnding. A final line ending
+// terminates the last line rather than starting an empty one, so "a\nb\n"
+// and "a\nb" both yield ["a", "b"], and empty content yields no lines.
+func splitLines(content, lineEnding string) []string {
+	if content == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(content, lineEnding), lineEnding)
+}
+
+// isLocalDaemon reports whether the client talks to the daemon over a unix
+// socket, and so whether its bind mount sources are paths on this host.
+func isLocalDaemon(dockerClient *client.Client) bool {
+	hostURL, err := client.ParseHostURL(dockerClient.DaemonHost())
+	return err == nil && hostURL.Scheme == "unix"
+}
+
+// hostPathForBindMount returns the host path backing containerPath when it
+// falls under one of the container's bind mounts, using the most specific
+// mount when they are nested.
+func hostPathForBindMount(mounts []container.MountPoint, containerPath string) (string, bool) {
+	var match *container.MountPoint
+	for i, m := range mounts {
+		if m.Type != mount.TypeBind {
+			continue
+		}
+
+		if containerPath != m.Destination && !strings.HasPrefix(containerPath, strings.TrimSuffix(m.Destination, "/")+"/") {
+			continue
+		}
+
+		if match == nil || len(m.Destination) > len(match.Destination) {
+			match = &mounts[i]
+		}
+	}
+
+	if match == nil {
+		return "", false
+	}
+
+	rel := strings.TrimPrefix(containerPath, match.Destination)
+
+	return filepath.Join(match.Source, filepath.FromSlash(rel)), true
+}
+
+// openHostFile opens a regular file on this host as a single-entry tar stream
+// with its stat, matching what CopyFromContainer returns for a file. Symlinks
+// anywhere in the path are rejected, as the container would resolve them
+// within its own filesystem rather than the host's.
+func openHostFile(hostPath string) (io.ReadCloser, container.PathStat, error) {
+	resolved, err := filepath.EvalSymlinks(hostPath)
+	if err != nil {
+		return nil, container.PathStat{}, err
+	}
+	if resolved != filepath.Clean(hostPath) {
+		return nil, container.PathStat{}, fmt.Errorf("%s is reached through a symlink", hostPath)
+	}
+
+	file, err := os.Open(hostPath)
+	if err != nil {
+		return nil, container.PathStat{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, container.PathStat{}, errors.Join(err, file.Close())
+	}
+	if !info.Mode().IsRegular() {
+		return nil, container.PathStat{}, errors.Join(fmt.Errorf("%s is not a regular file", hostPath), file.Close())
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return nil, container.PathStat{}, errors.Join(err, file.Close())
+	}
+
+	stat := container.PathStat{
+		Name:  info.Name(),
+		Size:  info.Size(),
+		Mode:  info.Mode(),
+		Mtime: info.ModTime(),
+	}
+
+	// stream the archive so the file is never held in memory twice
+	reader, writer := io.Pipe()
+	go func() {
+		defer file.Close()
+
+		tw := tar.NewWriter(writer)
+		err := tw.WriteHeader(hdr)
+		if err == nil {
+			_, err = io.Copy(tw, file)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	return reader, stat, nil
+}
+
+// dereferenceStat returns the stat of the target of stat when it describes a
+// symlink, and stat itself otherwise. The daemon reports the fully resolved
+// link target, so a single stat follows a chain of links. A dangling link
+// fails with a not-found error.
+func dereferenceStat(ctx context.Context, dockerClient *client.Client, containerName string, stat container.PathStat) (container.PathStat, error) {
+	if stat.Mode&os.ModeSymlink == 0 {
+		return stat, nil
+	}
+
+	return dockerClient.ContainerStatPath(ctx, containerName, stat.LinkTarget)
+}
+
+// waitForCopyFromContainer retries CopyFromContainer with exponential backoff
+// while the container or path does not exist yet, until the timeout elapses.
+// Errors other than not-found are returned immediately.
+func waitForCopyFromContainer(ctx context.Context, dockerClient *client.Client, containerName, path string, timeout time.Duration) (io.ReadCloser, container.PathStat, error) {
+	// The timeout only bounds the waiting: the returned stream stays tied
+	// to ctx so it can still be read after a successful attempt.
+	var file io.ReadCloser
+	var stat container.PathStat
+	var notFound error
+
+	err := PollUntil(ctx, WaitInitialInterval, WaitMaxInterval, timeout, func() (bool, error) {
+		var err error
+		file, stat, err = dockerClient.CopyFromContainer(ctx, containerName, path)
+		if cerrdefs.IsNotFound(err) {
+			notFound = err
+			return false, nil
+		}
+		return err == nil, err
+	})
+
+	var timedOut *WaitTimeoutError
+	if errors.As(err, &timedOut) {
+		return nil, stat, fmt.Errorf("%w waiting for path: %w", err, notFound)
+	}
+	if err != nil {
+		return nil, stat, err
+	}
+
+	return file, stat, nil
+}
+
+// resolvePathGlob returns the path of the single file in the directory of
+// pattern, a path cleaned by sanitizePath, whose name matches the last element
+// of pattern. Subdirectories never match. A path named pattern that exists is
+// returned as it is, so literal names containing glob characters still work.
+// The directory must be below the container root; only the last element may
+// be a pattern. The daemon can only copy the directory's whole subtree, so
+// its content is streamed and discarded to list the direct entries.
+func resolvePathGlob(ctx context.Context, dockerClient *client.Client, containerName, pattern string, maxResponseBytes int64) (string, error) {
+	_, err := dockerClient.ContainerStatPath(ctx, containerName, "/"+pattern)
+	if err == nil {
+		return pattern, nil
+	}
+	if !cerrdefs.IsNotFound(err) {
+		return "", err
+	}
+
+	dir, base := path.Split(pattern)
+	dir = path.Clean(dir)
+
+	if dir == "." {
+		return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("glob %q must be in a directory below the container root", "/"+pattern)}
+	}
+	if strings.ContainsAny(dir, "*?[") {
+		return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("only the last element of %q may be a glob", "/"+pattern)}
+	}
+	if _, err := path.Match(base, ""); err != nil {
+		return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("invalid glob %q: %v", base, err)}
+	}
+
+	listing, _, err := dockerClient.CopyFromContainer(ctx, containerName, "/"+dir)
+	if err != nil {
+		return "", wrapNotFound("container path", containerName+":/"+dir, err)
+	}
+	defer listing.Close()
+
+	tr, err := newTarReader(newLimitedReader(listing, maxResponseBytes))
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := extractAllFilesFromTar(tr, TarContentNone)
+	if err != nil {
+		return "", err
+	}
+
+	// entries are named relative to the parent of dir, e.g. app/x.conf for
+	// /etc/app, and directories, including dir itself, keep a trailing slash
+	var matches []string
+	for name := range entries {
+		if strings.HasSuffix(name, "/") || path.Dir(name) != path.Base(dir) {
+			continue
+		}
+		if matched, _ := path.Match(base, path.Base(name)); matched {
+			matches = append(matches, path.Join(dir, path.Base(name)))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", &NotFoundError{Resource: "file matching", Name: containerName + ":/" + pattern}
+	case 1:
+		return matches[0], nil
+	default:
+		slices.Sort(matches)
+		return "", fmt.Errorf("%d files match, expected exactly one: %v", len(matches), matches)
+	}
+}
+
+// statDirectory stats a directory in the container along with its tar header,
+// which carries the owner the stat lacks. Only the directory's own entry is
+// read; the stream is closed before the rest of the directory is transferred.
+func statDirectory(ctx context.Context, dockerClient *client.Client, containerName, dirPath string) (container.PathStat, *tar.Header, error) {
+	dir, stat, err := dockerClient.CopyFromContainer(ctx, containerName, dirPath)
+	if err != nil {
+		return container.PathStat{}, nil, err
+	}
+	defer dir.Close()
+
+	tr, err := newTarReader(dir)
+	if err != nil {
+		return container.PathStat{}, nil, err
+	}
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return container.PathStat{}, nil, fmt.Errorf("failed to read directory entry: %w", err)
+	}
+
+	return stat, hdr, nil
+}
+
+// pathChangedInContainer reports whether the writable layer of a container
+// adds or modifies containerPath, an absolute path.
+func pathChangedInContainer(ctx context.Context, dockerClient *client.Client, containerName, containerPath string) (bool, error) {
+	changes, err := dockerClient.ContainerDiff(ctx, containerName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, change := range changes {
+		if change.Path == containerPath && change.Kind != container.ChangeDelete {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// readImageFile reads a regular file as it is in an image, through an
+// unstarted container of the image which is removed afterwards. found is
+// false when the image has no regular file at path.
+func readImageFile(ctx context.Context, dockerClient *client.Client, imageID, path string, maxResponseBytes int64) (content []byte, found bool, err error) {
+	// The container is never started, so its command only needs to be
+	// present for images that don't define one.
+	created, err := dockerClient.ContainerCreate(ctx, &container.Config{
+		Image:      imageID,
+		Entrypoint: []string{"/"},
+	}, nil, nil, nil, "")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create image container: %w", err)
+	}
+	defer func() {
+		// removal must still run when the read itself was cancelled
+		removeErr := dockerClient.ContainerRemove(context.WithoutCancel(ctx), created.ID, container.RemoveOptions{Force: true})
+		err = errors.Join(err, removeErr)
+	}()
+
+	file, _, err := dockerClient.CopyFromContainer(ctx, created.ID, path)
+	if cerrdefs.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	tr, err := newTarReader(newLimitedReader(file, maxResponseBytes))
+	if err != nil {
+		return nil, false, err
+	}
+
+	files, err := extractAllFilesFromTar(tr, TarContentFull)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, info := range files {
+		if info.Header.Typeflag == tar.TypeReg {
+			return info.Content, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// snapshotContainer commits a container to a temporary image and creates an
+// unstarted container from it, returning that container's ID and a cleanup
+// function which removes both. Reads from the returned container are not
+// affected by writes made to the original after the snapshot was taken.
+func snapshotContainer(ctx context.Context, dockerClient *client.Client, containerName string) (string, func() error, error) {
+	committed, err := dockerClient.ContainerCommit(ctx, containerName, container.CommitOptions{
+		Comment: "terraform-provider-docker temporary snapshot",
+		Pause:   true,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to commit container: %w", err)
+	}
+
+	// cleanup must still run when the read itself was cancelled
+	cleanupCtx := context.WithoutCancel(ctx)
+
+	removeImage := func() error {
+		_, err := dockerClient.ImageRemove(cleanupCtx, committed.ID, image.RemoveOptions{Force: true, PruneChildren: true})
+		return err
+	}
+
+	// The container is never started, so its command only needs to be
+	// present for images that don't define one.
+	created, err := dockerClient.ContainerCreate(ctx, &container.Config{
+		Image:      committed.ID,
+		Entrypoint: []string{"/"},
+	}, nil, nil, nil, "")
+	if err != nil {
+		return "", nil, errors.Join(fmt.Errorf("failed to create snapshot container: %w", err), removeImage())
+	}
+
+	cleanup := func() error {
+		if err := dockerClient.ContainerRemove(cleanupCtx, created.ID, container.RemoveOptions{Force: true}); err != nil {
+			return err
+		}
+		return removeImage()
+	}
+
+	return created.ID, cleanup, nil
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// newTestClient returns a client talking to a fake daemon served by handler.
+// The API version is fixed so the client never pings the daemon.
+func newTestClient(t *testing.T, handler http.Handler) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	dockerClient, err := client.NewClientWithOpts(
+		client.WithHost("tcp://"+strings.TrimPrefix(server.URL, "http://")),
+		client.WithHTTPClient(server.Client()),
+		client.WithVersion("1.44"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { dockerClient.Close() })
+
+	return dockerClient
+}
+
+// writeDaemonError writes an error response the way the daemon does.
+func writeDaemonError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// statHandler serves HEAD requests on the archive endpoint of container web
+// with the stats of the given paths, and 404 for any other path.
+func statHandler(t *testing.T, stats map[string]container.PathStat) http.Handler {
+	return archiveHandler(t, stats, nil)
+}
+
+// archiveHandler serves the archive endpoint of container web: HEAD requests
+// with the stats of the given paths, and GET requests with their stat and
+// archive. Any other path is answered with 404.
+func archiveHandler(t *testing.T, stats map[string]container.PathStat, archives map[string][]byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead && r.Method != http.MethodGet || r.URL.Path != "/v1.44/containers/web/archive" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			writeDaemonError(w, http.StatusNotImplemented, "unexpected request")
+			return
+		}
+
+		path := r.URL.Query().Get("path")
+		stat, ok := stats[path]
+		archive, archived := archives[path]
+		if !ok || r.Method == http.MethodGet && !archived {
+			writeDaemonError(w, http.StatusNotFound, "Could not find the file in container web")
+			return
+		}
+
+		encoded, err := json.Marshal(stat)
+		if err != nil {
+			t.Errorf("failed to encode stat: %v", err)
+			return
+		}
+		w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(encoded))
+
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/x-tar")
+			w.Write(archive)
+		}
+	})
+}
+
+func TestDereferenceStat(t *testing.T) {
+	link := container.PathStat{Name: "current", Mode: os.ModeSymlink | 0o777, LinkTarget: "/srv/releases/v2/config.yml"}
+	target := container.PathStat{Name: "config.yml", Size: 42, Mode: 0o644}
+
+	dockerClient := newTestClient(t, statHandler(t, map[string]container.PathStat{
+		"/srv/releases/v2/config.yml": target,
+	}))
+
+	got, err := dereferenceStat(context.Background(), dockerClient, "web", link)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != target.Name || got.Size != target.Size || got.Mode != target.Mode {
+		t.Errorf("expected the stat of the target %+v, got %+v", target, got)
+	}
+}
+
+func TestDereferenceStatRegularFile(t *testing.T) {
+	file := container.PathStat{Name: "config.yml", Size: 42, Mode: 0o644}
+
+	// a regular file is returned as it is, without asking the daemon
+	dockerClient := newTestClient(t, statHandler(t, nil))
+
+	got, err := dereferenceStat(context.Background(), dockerClient, "web", file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != file {
+		t.Errorf("expected %+v, got %+v", file, got)
+	}
+}
+
+func TestDereferenceStatDanglingLink(t *testing.T) {
+	link := container.PathStat{Name: "current", Mode: os.ModeSymlink | 0o777, LinkTarget: "/srv/releases/v1/config.yml"}
+
+	dockerClient := newTestClient(t, statHandler(t, nil))
+
+	_, err := dereferenceStat(context.Background(), dockerClient, "web", link)
+	if !cerrdefs.IsNotFound(err) {
+		t.Fatalf("expected a not-found error for a dangling link, got: %v", err)
+	}
+}
+
+func TestResolvePathGlob(t *testing.T) {
+	listing := buildTar(t,
+		tarEntry{name: "app/", typeflag: tar.TypeDir},
+		tarEntry{name: "app/a.conf", content: "a"},
+		tarEntry{name: "app/b.conf", content: "b"},
+		tarEntry{name: "app/readme", content: "readme"},
+		tarEntry{name: "app/pages/", typeflag: tar.TypeDir},
+		tarEntry{name: "app/pages/c.conf", content: "c"},
+		tarEntry{name: "app/[id].js", content: "id"},
+	)
+
+	dockerClient := newTestClient(t, archiveHandler(t,
+		map[string]container.PathStat{
+			"/etc/app":         {Name: "app", Mode: os.ModeDir | 0o755},
+			"/etc/app/[id].js": {Name: "[id].js", Mode: 0o644},
+		},
+		map[string][]byte{"/etc/app": listing},
+	))
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr string
+	}{
+		{name: "no match", pattern: "etc/app/*.yml", wantErr: "does not exist"},
+		{name: "subdirectories never match", pattern: "etc/app/pag*", wantErr: "does not exist"},
+		{name: "one match", pattern: "etc/app/a*.conf", want: "etc/app/a.conf"},
+		{name: "many matches", pattern: "etc/app/*.conf", wantErr: "2 files match, expected exactly one: [etc/app/a.conf etc/app/b.conf]"},
+		{name: "existing literal path", pattern: "etc/app/[id].js", want: "etc/app/[id].js"},
+		{name: "glob in a directory", pattern: "etc/*/a.conf", wantErr: "only the last element"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePathGlob(context.Background(), dockerClient, "web", tt.pattern, 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %q, %v", tt.wantErr, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
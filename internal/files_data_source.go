@@ -2,8 +2,21 @@ package internal
 
 import (
 	"archive/tar"
+	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
@@ -11,18 +24,75 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+// DefaultFilesConcurrency is the number of paths read at once when concurrency is unset
+const DefaultFilesConcurrency = 4
+
+// Content modes, controlling how much of each file is stored in state
+const (
+	FilesContentModeFull     = "full"
+	FilesContentModeChecksum = "checksum"
+	FilesContentModeNone     = "none"
+)
+
+// filesEntryAttrTypes are the attribute types of a file in docker_files
+var filesEntryAttrTypes = map[string]attr.Type{
+	"content":         types.StringType,
+	"content_present": types.BoolType,
+	"raw_content":     types.StringType,
+	"sha256":          types.StringType,
+	"gid":             types.Int32Type,
+	"mod_time":        types.StringType,
+	"mode":            types.Int64Type,
+	"name":            types.StringType,
+	"size":            types.Int64Type,
+	"uid":             types.Int32Type,
+	"type":            types.StringType,
+	"hardlink_target": types.StringType,
+	"xattrs":          types.MapType{ElemType: types.StringType},
+	"local_path":      types.StringType,
+}
+
 type FilesDataSource struct {
 	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+	DaemonOS     string
+
+	MaxResponseBytes int64
 }
 
 type FilesDataSourceModel struct {
-	Container types.String `tfsdk:"container"`
-	Path      types.String `tfsdk:"path"`
-	Files     types.Map    `tfsdk:"files"`
-	Stat      types.Object `tfsdk:"stat"`
+	Container      types.String `tfsdk:"container"`
+	Label          types.Map    `tfsdk:"label"`
+	Host           types.String `tfsdk:"host"`
+	Timeout        types.Int32  `tfsdk:"timeout"`
+	Path           types.String `tfsdk:"path"`
+	Paths          types.List   `tfsdk:"paths"`
+	Concurrency    types.Int32  `tfsdk:"concurrency"`
+	ContentMode    types.String `tfsdk:"content_mode"`
+	IncludeArchive types.Bool   `tfsdk:"include_archive"`
+	OutputDir      types.String `tfsdk:"output_dir"`
+	MetadataOnly   types.Bool   `tfsdk:"metadata_only"`
+	ArchiveBase64  types.String `tfsdk:"archive_base64"`
+	Files          types.Map    `tfsdk:"files"`
+	Directories    types.List   `tfsdk:"directories"`
+	Stat           types.Object `tfsdk:"stat"`
+	TotalSize      types.Int64  `tfsdk:"total_size"`
+	FileCount      types.Int64  `tfsdk:"file_count"`
+	DirCount       types.Int64  `tfsdk:"dir_count"`
+
+	NormalizeLineEndings types.Bool `tfsdk:"normalize_line_endings"`
+	TrimTrailingNewline  types.Bool `tfsdk:"trim_trailing_newline"`
+
+	SkipExistenceCheck types.Bool `tfsdk:"skip_existence_check"`
+
+	IncludeEntries types.Bool `tfsdk:"include_entries"`
+	Entries        types.List `tfsdk:"entries"`
+
+	Fields types.List `tfsdk:"fields"`
 }
 
 func NewFilesDataSource() datasource.DataSource {
@@ -39,19 +109,154 @@ func (d *FilesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 			Retrieve files' stats and contents from a docker container.
 
 			Returns all files in the specified path as a map.
+
+			Alternatively, set paths to read a list of explicit paths concurrently,
+			one copy per path, instead of a single directory.
 		`,
 		Attributes: map[string]schema.Attribute{
 
-			// Required
+			// Optional
 
 			"container": schema.StringAttribute{
-				Required:    true,
-				Description: "The name of the container",
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the container, resolved from label when not set",
+			},
+
+			"label": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels identifying exactly one container, as an alternative to container (e.g. compose service labels)",
+				ElementType: types.StringType,
 			},
 
 			"path": schema.StringAttribute{
-				Required:    true,
-				Description: "The filepath to request from the container",
+				Optional:    true,
+				Description: "The filepath to request from the container. When the provider's daemon runs Windows containers, paths may use a drive letter and either separator. At least one of path or paths must be set",
+			},
+
+			"paths": schema.ListAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					A list of filepaths to request from the container, each copied
+					separately and concurrently. At least one of path or paths must be
+					set.
+
+					When path is also set, paths are relative to it and only those files
+					are copied instead of the whole directory, with files keyed as if the
+					directory had been copied. Otherwise files are keyed by their path
+					relative to the container root. stat is null.
+				`,
+				ElementType: types.StringType,
+			},
+
+			"concurrency": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum number of paths read at once when paths is set
+
+					Default: 4
+				`,
+			},
+
+			"skip_existence_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip inspecting the container up front, saving a request at the cost of a less specific error when it is missing",
+			},
+
+			"content_mode": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					How much of each file to store in state: "full" stores the content
+					and its sha256, "checksum" stores only the sha256 so drift can still
+					be detected, and "none" stores only the file metadata. With
+					"checksum" the content is hashed as it is streamed instead of being
+					read into memory, so the 10MB file size limit does not apply.
+
+					Default: "full"
+				`,
+			},
+
+			"include_archive": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to capture the raw tar stream returned for path into
+					archive_base64, preserving metadata (xattrs, entry order) that the
+					extracted files discard. Not supported with paths.
+
+					Default: false
+				`,
+			},
+
+			"fields": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: `
+					The attributes of each file in files and entries to compute, e.g.
+					["name", "size"], leaving the others null to save state size and CPU
+					when listing large directories
+
+					Default: all attributes
+				`,
+			},
+
+			"include_entries": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to also return the files as entries, a list in the order of
+					the tar stream, which the files map loses. Each file is then stored
+					in state twice.
+
+					Default: false
+				`,
+			},
+
+			"metadata_only": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to return only file metadata for fast directory listings,
+					skipping file content as it is streamed instead of reading it into
+					memory. content and sha256 are null, and the 10MB file size limit
+					does not apply. Not supported with output_dir or a content_mode other
+					than "none".
+
+					Default: false
+				`,
+			},
+
+			"output_dir": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					A local directory to write the files to instead of holding their
+					content in memory, for directories too large to read at once. Files
+					keep their relative paths and permissions, content is always null,
+					and local_path records where each file was written. Only regular
+					files, directories and hardlinks are written. Not supported with
+					include_archive.
+				`,
+			},
+
+			"normalize_line_endings": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to convert CRLF line endings in content to LF, so files edited on Windows don't produce diffs in rendered templates",
+			},
+
+			"trim_trailing_newline": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to remove a single final line ending (\"\\n\" or \"\\r\\n\") from content, e.g. before interpolating it into a heredoc",
+			},
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds the whole read may take, including waiting for a free request
+					slot, on top of the provider's per-request timeout. Unset means no
+					limit.
+				`,
 			},
 
 			// Computed
@@ -60,69 +265,60 @@ func (d *FilesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				Computed:    true,
 				Description: "All files returned from the path",
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"content": schema.StringAttribute{
-							Computed:    true,
-							Sensitive:   true,
-							Description: "The file content",
-						},
-						"mod_time": schema.StringAttribute{
-							Computed:    true,
-							Description: "The file modification time",
-						},
-						"mode": schema.Int64Attribute{
-							Computed:    true,
-							Description: "The file mode",
-						},
-						"name": schema.StringAttribute{
-							Computed:    true,
-							Description: "The file name",
-						},
-						"size": schema.Int64Attribute{
-							Computed:    true,
-							Description: "The file size",
-						},
-						"uid": schema.Int32Attribute{
-							Computed:    true,
-							Description: "The file owner UID",
-						},
-						"gid": schema.Int32Attribute{
+					Attributes: filesEntrySchemaAttributes(),
+				},
+			},
+
+			"entries": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The files in the order of the tar stream, for tools that depend on entry order; null unless include_entries is set",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: func() map[string]schema.Attribute {
+						attributes := filesEntrySchemaAttributes()
+						attributes["key"] = schema.StringAttribute{
 							Computed:    true,
-							Description: "The file owner GID",
-						},
-						"type": schema.StringAttribute{
+							Description: "The key of the file in files",
+						}
+						attributes["index"] = schema.Int64Attribute{
 							Computed:    true,
-							Description: "The file type",
-						},
-					},
+							Description: "The position of the entry in the tar stream, counting from 0; with paths, entries of later paths follow those of earlier ones",
+						}
+						return attributes
+					}(),
 				},
 			},
 
+			"total_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The sum of the sizes of the regular files returned, in bytes",
+			},
+
+			"file_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of regular files returned",
+			},
+
+			"dir_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of directories returned",
+			},
+
+			"directories": schema.ListAttribute{
+				Computed:    true,
+				Description: "The names of the directory entries returned from the path, sorted, to reconstruct the tree",
+				ElementType: types.StringType,
+			},
+
+			"archive_base64": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The base64-encoded tar stream returned for path, up to 10MB; null unless include_archive is set",
+			},
+
 			"stat": schema.SingleNestedAttribute{
 				Computed:    true,
 				Description: "Stat for file path",
-				Attributes: map[string]schema.Attribute{
-					"name": schema.StringAttribute{
-						Computed:    true,
-						Description: "The file name",
-					},
-					"size": schema.Int64Attribute{
-						Computed:    true,
-						Description: "The file size",
-					},
-					"mode": schema.Int32Attribute{
-						Computed:    true,
-						Description: "The file mode",
-					},
-					"mtime": schema.StringAttribute{
-						Computed:    true,
-						Description: "The file modification time",
-					},
-					"link_target": schema.StringAttribute{
-						Computed:    true,
-						Description: "The file link target",
-					},
-				},
+				Attributes:  statSchemaAttributes(),
 			},
 		},
 	}
@@ -144,6 +340,10 @@ func (d *FilesDataSource) Configure(ctx context.Context, req datasource.Configur
 	}
 
 	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+	d.DaemonOS = config.DaemonOS
+	d.MaxResponseBytes = config.MaxResponseBytes
 }
 
 func (d *FilesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -155,110 +355,686 @@ func (d *FilesDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	// Validate container name
-	if err := validateContainerName(data.Container.ValueString()); err != nil {
+	ref := ContainerRef{Name: data.Container.ValueString()}
+	resp.Diagnostics.Append(data.Label.ElementsAs(ctx, &ref.Labels, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := ref.Validate(); err != nil {
 		resp.Diagnostics.AddError(
-			"Invalid Container Name",
-			fmt.Sprintf("Container name validation failed: %v", err),
+			"Invalid Container Selector",
+			err.Error(),
 		)
 		return
 	}
 
-	// Validate and sanitize path
-	sanitizedPath, err := sanitizePath(data.Path.ValueString())
-	if err != nil {
+	var paths []string
+	if !data.Paths.IsNull() {
+		resp.Diagnostics.Append(data.Paths.ElementsAs(ctx, &paths, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.Path.IsNull() && len(paths) == 0 {
 		resp.Diagnostics.AddError(
 			"Invalid File Path",
-			fmt.Sprintf("Path validation failed for %q: %v", data.Path.ValueString(), err),
+			"At least one of path or paths must be set",
+		)
+		return
+	}
+
+	contentMode := FilesContentModeFull
+	if !data.ContentMode.IsNull() {
+		contentMode = data.ContentMode.ValueString()
+	}
+
+	if contentMode != FilesContentModeFull && contentMode != FilesContentModeChecksum && contentMode != FilesContentModeNone {
+		resp.Diagnostics.AddError(
+			"Invalid Content Mode",
+			fmt.Sprintf("content_mode must be %q, %q or %q, got: %q", FilesContentModeFull, FilesContentModeChecksum, FilesContentModeNone, contentMode),
+		)
+		return
+	}
+
+	var fields []string
+	if !data.Fields.IsNull() {
+		resp.Diagnostics.Append(data.Fields.ElementsAs(ctx, &fields, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := filesEntryAttrTypes[field]; !ok {
+			resp.Diagnostics.AddError(
+				"Invalid Fields",
+				fmt.Sprintf("fields must only name attributes of files, got: %q", field),
+			)
+			return
+		}
+	}
+
+	// wantField reports whether the named attribute of each file is computed
+	wantField := func(name string) bool {
+		return data.Fields.IsNull() || slices.Contains(fields, name)
+	}
+
+	includeArchive := data.IncludeArchive.ValueBool()
+
+	if includeArchive && len(paths) > 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Archive Option",
+			"include_archive is only supported with path, as paths are copied as separate archives",
+		)
+		return
+	}
+
+	outputDir := data.OutputDir.ValueString()
+
+	if outputDir != "" && includeArchive {
+		resp.Diagnostics.AddError(
+			"Invalid Archive Option",
+			"include_archive is not supported with output_dir, as the archive is held in memory",
+		)
+		return
+	}
+
+	metadataOnly := data.MetadataOnly.ValueBool()
+
+	if metadataOnly && outputDir != "" {
+		resp.Diagnostics.AddError(
+			"Invalid Metadata Only Option",
+			"metadata_only is not supported with output_dir, as output_dir writes file content",
+		)
+		return
+	}
+
+	if metadataOnly && !data.ContentMode.IsNull() && contentMode != FilesContentModeNone {
+		resp.Diagnostics.AddError(
+			"Invalid Metadata Only Option",
+			fmt.Sprintf("metadata_only is only supported with content_mode %q, got: %q", FilesContentModeNone, contentMode),
+		)
+		return
+	}
+
+	// checksums are streamed so they aren't limited by MaxFileSize
+	tarContent := TarContentFull
+	switch {
+	case metadataOnly:
+		tarContent = TarContentNone
+	case contentMode == FilesContentModeChecksum:
+		tarContent = TarContentChecksum
+	}
+
+	concurrency := int32(DefaultFilesConcurrency)
+	if !data.Concurrency.IsNull() {
+		concurrency = data.Concurrency.ValueInt32()
+	}
+
+	if concurrency < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Concurrency",
+			fmt.Sprintf("concurrency must be at least 1, got: %d", concurrency),
+		)
+		return
+	}
+
+	// Validate and sanitize paths
+	sanitizedPaths := make([]string, len(paths))
+	for i, filePath := range paths {
+		sanitizedPath, err := sanitizePath(filePath, d.DaemonOS)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid File Path",
+				err.Error(),
+			)
+			return
+		}
+		sanitizedPaths[i] = sanitizedPath
+	}
+
+	// Validate and sanitize path before any daemon request, keeping a trailing
+	// "/" or "/." as it changes whether the directory or its contents are copied
+	var sanitizedPath string
+	if !data.Path.IsNull() {
+		var err error
+		sanitizedPath, err = sanitizePath(data.Path.ValueString(), d.DaemonOS)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid File Path",
+				err.Error(),
+			)
+			return
+		}
+
+		separator, suffixes := "/", []string{"/.", "/"}
+		if d.DaemonOS == DaemonOSWindows {
+			separator, suffixes = `\`, []string{"/.", `\.`, "/", `\`}
+		}
+
+		// copy only the listed files under path rather than the whole directory
+		for i := range sanitizedPaths {
+			sanitizedPaths[i] = strings.TrimSuffix(sanitizedPath, separator) + separator + sanitizedPaths[i]
+		}
+
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(data.Path.ValueString(), suffix) {
+				sanitizedPath = strings.TrimSuffix(sanitizedPath, separator) + separator + suffix[1:]
+				break
+			}
+		}
+	}
+
+	if !data.Timeout.IsNull() {
+		if data.Timeout.ValueInt32() < 1 {
+			resp.Diagnostics.AddError(
+				"Invalid Timeout",
+				fmt.Sprintf("timeout must be at least 1, got: %d", data.Timeout.ValueInt32()),
+			)
+			return
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(data.Timeout.ValueInt32())*time.Second)
+		defer cancel()
+
+		// added alongside the error of whichever request was cut short
+		defer func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				resp.Diagnostics.AddError(
+					"Read Timed Out",
+					fmt.Sprintf("docker_files did not finish reading from container %q within the %d second timeout", data.Container.ValueString(), data.Timeout.ValueInt32()),
+				)
+			}
+		}()
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
 		)
 		return
 	}
+	release = sync.OnceFunc(release)
+	defer release()
 
-	file, stat, err := d.DockerClient.CopyFromContainer(ctx, data.Container.ValueString(), sanitizedPath)
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read File from Container",
-			fmt.Sprintf("Error reading file %q from container %q: %v", data.Path.ValueString(), data.Container.ValueString(), err),
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
 		)
 		return
 	}
 	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
+		if closeErr := closeClient(); closeErr != nil {
 			resp.Diagnostics.AddWarning(
 				"Resource Cleanup Warning",
-				fmt.Sprintf("Failed to close file stream for %q from container %q: %v", data.Path.ValueString(), data.Container.ValueString(), closeErr),
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
 			)
 		}
 	}()
 
-	data.Stat = types.ObjectValueMust(
-		map[string]attr.Type{
-			"name":        types.StringType,
-			"size":        types.Int64Type,
-			"mode":        types.Int32Type,
-			"mtime":       types.StringType,
-			"link_target": types.StringType,
-		},
-
-		map[string]attr.Value{
-			"name":        types.StringValue(stat.Name),
-			"size":        types.Int64Value(stat.Size),
-			"mode":        types.Int32Value(int32(stat.Mode)),
-			"mtime":       types.StringValue(stat.Mtime.Format(time.RFC3339)),
-			"link_target": types.StringValue(stat.LinkTarget),
-		},
-	)
-
-	tr := tar.NewReader(file)
-	allFiles, err := extractAllFilesFromTar(tr)
+	name, err := ResolveContainer(ctx, dockerClient, ref)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Extract Files from Tar",
-			fmt.Sprintf("Error extracting files from tar stream for %q: %v", data.Path.ValueString(), err),
+			"Unable to Resolve Container",
+			formatError("find", fmt.Sprintf("a container with labels %v", ref.Labels), "", "", err),
 		)
 		return
 	}
 
-	attrTypes := map[string]attr.Type{
-		"content":  types.StringType,
-		"gid":      types.Int32Type,
-		"mod_time": types.StringType,
-		"mode":     types.Int64Type,
-		"name":     types.StringType,
-		"size":     types.Int64Type,
-		"uid":      types.Int32Type,
-		"type":     types.StringType,
+	data.Container = types.StringValue(name)
+
+	// containers resolved from labels are already known to exist
+	if !data.SkipExistenceCheck.ValueBool() && data.Label.IsNull() {
+		err := checkContainerExists(ctx, dockerClient, data.Container.ValueString())
+
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Container Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+	}
+
+	var allFiles map[string]*FileInfo
+	data.ArchiveBase64 = types.StringNull()
+
+	if len(sanitizedPaths) > 0 {
+		// each copy takes its own slot, so the fan-out stays within the limit
+		release()
+
+		allFiles, err = copyPathsFromContainer(ctx, dockerClient, d.Limiter, data.Container.ValueString(), sanitizedPaths, int(concurrency), outputDir, tarContent, d.MaxResponseBytes)
+		if err == nil && !data.Path.IsNull() {
+			allFiles = rekeyFromParent(allFiles, containerParentDir(sanitizedPath, d.DaemonOS))
+		}
+
+		var tooLarge *ResponseTooLargeError
+		if errors.As(err, &tooLarge) {
+			resp.Diagnostics.AddError(
+				"Daemon Response Too Large",
+				formatError("read", "files", data.Container.ValueString(), "", err),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read Files from Container",
+				formatError("read", "files", data.Container.ValueString(), "", err),
+			)
+			return
+		}
+
+		data.Stat = types.ObjectNull(statAttrTypes)
+	} else {
+		file, stat, err := dockerClient.CopyFromContainer(ctx, data.Container.ValueString(), sanitizedPath)
+		err = wrapNotFound("container path", data.Container.ValueString()+":"+data.Path.ValueString(), err)
+
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Path Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read File from Container",
+				formatError("read", fmt.Sprintf("path %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil {
+				resp.Diagnostics.AddWarning(
+					"Resource Cleanup Warning",
+					formatError("close", fmt.Sprintf("file stream for %q", data.Path.ValueString()), data.Container.ValueString(), "", closeErr),
+				)
+			}
+		}()
+
+		data.Stat = statObjectValue(stat)
+
+		// the archive is captured as it is extracted, so the stream is only read once
+		var archive bytes.Buffer
+		stream := newLimitedReader(file, d.MaxResponseBytes)
+		if includeArchive {
+			stream = io.TeeReader(io.LimitReader(stream, MaxArchiveSize+1), &archive)
+		}
+
+		tr, err := newTarReader(stream)
+		if err == nil && outputDir != "" {
+			allFiles, err = extractAllFilesToDir(tr, outputDir)
+		} else if err == nil {
+			allFiles, err = extractAllFilesFromTar(tr, tarContent)
+		}
+		if err == nil && includeArchive {
+			// the tar reader stops at the end-of-archive marker, leaving padding unread
+			_, err = io.Copy(io.Discard, stream)
+		}
+
+		if archive.Len() > MaxArchiveSize {
+			resp.Diagnostics.AddError(
+				"Archive Too Large",
+				formatError("capture", fmt.Sprintf("tar stream for %q", data.Path.ValueString()), data.Container.ValueString(), fmt.Sprintf("it exceeds the maximum archive size of %d bytes", MaxArchiveSize), nil),
+			)
+			return
+		}
+
+		var tooLarge *ResponseTooLargeError
+		if errors.As(err, &tooLarge) {
+			resp.Diagnostics.AddError(
+				"Daemon Response Too Large",
+				formatError("read", fmt.Sprintf("path %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Extract Files from Tar",
+				formatError("extract", fmt.Sprintf("files from tar stream for %q", data.Path.ValueString()), data.Container.ValueString(), "", err),
+			)
+			return
+		}
+
+		if includeArchive {
+			data.ArchiveBase64 = types.StringValue(base64.StdEncoding.EncodeToString(archive.Bytes()))
+		}
 	}
 
 	fileAttrs := make(map[string]attr.Value)
+	directories := []string{}
+	var totalSize, fileCount int64
 	for fileName, fileInfo := range allFiles {
 
-		var content basetypes.StringValue
-		if fileInfo.Content == nil {
-			content = basetypes.NewStringNull()
-		} else {
-			content = types.StringValue(string(fileInfo.Content))
-		}
-
-		fileAttrs[fileName] = types.ObjectValueMust(
-			attrTypes,
-			map[string]attr.Value{
-				"content":  content,
-				"gid":      types.Int32Value(int32(fileInfo.Header.Gid)),
-				"mod_time": types.StringValue(fileInfo.Header.ModTime.Format(time.RFC3339)),
-				"mode":     types.Int64Value(fileInfo.Header.Mode),
-				"name":     types.StringValue(fileInfo.Header.Name),
-				"size":     types.Int64Value(fileInfo.Header.Size),
-				"uid":      types.Int32Value(int32(fileInfo.Header.Uid)),
-				"type":     types.StringValue(string(fileInfo.Header.Typeflag)),
-			},
-		)
+		switch fileInfo.Header.Typeflag {
+		case tar.TypeDir:
+			directories = append(directories, fileName)
+		case tar.TypeReg:
+			totalSize += fileInfo.Header.Size
+			fileCount++
+		}
+
+		content, rawContent := types.StringNull(), types.StringNull()
+		if fileInfo.Content != nil && contentMode == FilesContentModeFull && wantField("content") {
+			content = types.StringValue(normalizeContent(string(fileInfo.Content), data.NormalizeLineEndings.ValueBool(), data.TrimTrailingNewline.ValueBool()))
+		}
+		if fileInfo.Content != nil && contentMode == FilesContentModeFull && wantField("raw_content") {
+			if data.NormalizeLineEndings.ValueBool() || data.TrimTrailingNewline.ValueBool() {
+				rawContent = types.StringValue(string(fileInfo.Content))
+			}
+		}
+
+		checksum := types.StringNull()
+		switch {
+		case !wantField("sha256") || contentMode == FilesContentModeNone:
+		case fileInfo.Content != nil:
+			sum := sha256.Sum256(fileInfo.Content)
+			checksum = types.StringValue(hex.EncodeToString(sum[:]))
+		case fileInfo.SHA256 != nil:
+			checksum = types.StringValue(hex.EncodeToString(fileInfo.SHA256))
+		}
+
+		localPath := types.StringNull()
+		if fileInfo.LocalPath != "" {
+			localPath = types.StringValue(fileInfo.LocalPath)
+		}
+
+		hardlinkTarget := types.StringNull()
+		if fileInfo.Header.Typeflag == tar.TypeLink {
+			hardlinkTarget = types.StringValue(fileInfo.Header.Linkname)
+		}
+
+		values := map[string]attr.Value{
+			"content":         content,
+			"content_present": types.BoolValue(fileInfo.Content != nil || outputDir == "" && fileInfo.SHA256 != nil),
+			"raw_content":     rawContent,
+			"sha256":          checksum,
+			"gid":             types.Int32Value(int32(fileInfo.Header.Gid)),
+			"mod_time":        types.StringNull(),
+			"mode":            types.Int64Value(fileInfo.Header.Mode),
+			"name":            types.StringValue(fileInfo.Header.Name),
+			"size":            types.Int64Value(fileInfo.Header.Size),
+			"uid":             types.Int32Value(int32(fileInfo.Header.Uid)),
+			"type":            types.StringValue(string(fileInfo.Header.Typeflag)),
+			"hardlink_target": hardlinkTarget,
+			"xattrs":          types.MapNull(types.StringType),
+			"local_path":      localPath,
+		}
+
+		// the costlier values are only built when wanted, the rest are nulled after
+		if wantField("mod_time") {
+			values["mod_time"] = types.StringValue(fileInfo.Header.ModTime.Format(time.RFC3339))
+		}
+		if wantField("xattrs") {
+			values["xattrs"] = xattrsMapValue(fileInfo.Header)
+		}
+		for name := range values {
+			if !wantField(name) {
+				values[name] = nullValue(ctx, filesEntryAttrTypes[name])
+			}
+		}
+
+		fileAttrs[fileName] = types.ObjectValueMust(filesEntryAttrTypes, values)
 	}
 
 	data.Files = types.MapValueMust(
-		types.ObjectType{AttrTypes: attrTypes},
+		types.ObjectType{AttrTypes: filesEntryAttrTypes},
 		fileAttrs,
 	)
 
+	entryAttrTypes := maps.Clone(filesEntryAttrTypes)
+	entryAttrTypes["key"] = types.StringType
+	entryAttrTypes["index"] = types.Int64Type
+
+	data.Entries = types.ListNull(types.ObjectType{AttrTypes: entryAttrTypes})
+
+	if data.IncludeEntries.ValueBool() {
+		fileNames := slices.SortedFunc(maps.Keys(allFiles), func(a, b string) int {
+			return cmp.Compare(allFiles[a].Index, allFiles[b].Index)
+		})
+
+		entries := make([]attr.Value, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			attributes := fileAttrs[fileName].(types.Object).Attributes()
+			attributes["key"] = types.StringValue(fileName)
+			attributes["index"] = types.Int64Value(int64(allFiles[fileName].Index))
+
+			entries = append(entries, types.ObjectValueMust(entryAttrTypes, attributes))
+		}
+
+		data.Entries = types.ListValueMust(types.ObjectType{AttrTypes: entryAttrTypes}, entries)
+	}
+
+	slices.Sort(directories)
+
+	directoryValues := []attr.Value{}
+	for _, directory := range directories {
+		directoryValues = append(directoryValues, types.StringValue(directory))
+	}
+
+	data.Directories = types.ListValueMust(types.StringType, directoryValues)
+
+	data.TotalSize = types.Int64Value(totalSize)
+	data.FileCount = types.Int64Value(fileCount)
+	data.DirCount = types.Int64Value(int64(len(directories)))
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// copyPathsFromContainer copies each path from the container with at most
+// concurrency copies in flight, merging the extracted files into one map keyed
+// by their path relative to the container root. Each copy holds a slot of
+// limiter while it runs. Errors for individual paths are joined so every
+// failing path is reported, and the results are merged with mergePathFiles.
+// When outputDir is set the files are written under it instead of being read
+// into memory, and otherwise content selects what is kept of them (see
+// extractFileFromTar). maxResponseBytes applies to each path's archive
+// separately.
+func copyPathsFromContainer(ctx context.Context, dockerClient *client.Client, limiter *RequestLimiter, containerName string, paths []string, concurrency int, outputDir string, content TarContent, maxResponseBytes int64) (map[string]*FileInfo, error) {
+	results := make([]map[string]*FileInfo, len(paths))
+	errs := make([]error, len(paths))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				release, err := limiter.Acquire(ctx)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i], errs[i] = copyPathFromContainer(ctx, dockerClient, containerName, paths[i], outputDir, content, maxResponseBytes)
+				release()
+			}
+		}()
+	}
+
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return mergePathFiles(results), nil
+}
+
+// mergePathFiles merges the files read for each path into one map, indexing
+// each path's entries after those of the paths before it. Overlapping paths,
+// like a directory and a file in it, yield the same entries more than once;
+// only the first is kept, and the indexes count the kept entries so they have
+// no gaps.
+func mergePathFiles(results []map[string]*FileInfo) map[string]*FileInfo {
+	allFiles := make(map[string]*FileInfo)
+	for _, files := range results {
+		names := slices.SortedFunc(maps.Keys(files), func(a, b string) int {
+			return cmp.Compare(files[a].Index, files[b].Index)
+		})
+
+		for _, name := range names {
+			if _, ok := allFiles[name]; ok {
+				continue
+			}
+			files[name].Index = len(allFiles)
+			allFiles[name] = files[name]
+		}
+	}
+
+	return allFiles
+}
+
+// rekeyFromParent makes files keyed relative to the container root relative
+// to parent instead, matching the keys of a copy of a directory in parent.
+func rekeyFromParent(files map[string]*FileInfo, parent string) map[string]*FileInfo {
+	prefix := strings.Trim(filepath.ToSlash(parent), "/")
+	if prefix == "" || prefix == "." {
+		return files
+	}
+	prefix += "/"
+
+	keyed := make(map[string]*FileInfo, len(files))
+	for name, fileInfo := range files {
+		keyed[strings.TrimPrefix(name, prefix)] = fileInfo
+	}
+
+	return keyed
+}
+
+// copyPathFromContainer copies a single path from the container. Tar entries
+// are named relative to the parent of the path, so they are prefixed with it
+// to keep files from different paths apart, both in the map and under outputDir.
+func copyPathFromContainer(ctx context.Context, dockerClient *client.Client, containerName string, filePath string, outputDir string, content TarContent, maxResponseBytes int64) (map[string]*FileInfo, error) {
+	file, _, err := dockerClient.CopyFromContainer(ctx, containerName, filePath)
+	if err != nil {
+		return nil, wrapNotFound("container path", containerName+":/"+filePath, err)
+	}
+	defer file.Close()
+
+	tr, err := newTarReader(newLimitedReader(file, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	parent := path.Dir(filePath)
+
+	var files map[string]*FileInfo
+	if outputDir != "" {
+		files, err = extractAllFilesToDir(tr, filepath.Join(outputDir, filepath.FromSlash(parent)))
+	} else {
+		files, err = extractAllFilesFromTar(tr, content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	keyed := make(map[string]*FileInfo, len(files))
+	for name, fileInfo := range files {
+		keyed[path.Join(parent, name)] = fileInfo
+	}
+
+	return keyed, nil
+}
+
+// nullValue returns the null value of attrType.
+func nullValue(ctx context.Context, attrType attr.Type) attr.Value {
+	value, _ := attrType.ValueFromTerraform(ctx, tftypes.NewValue(attrType.TerraformType(ctx), nil))
+	return value
+}
+
+// filesEntrySchemaAttributes returns the schema of a file returned by
+// docker_files, shared by files and entries.
+func filesEntrySchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"content": schema.StringAttribute{
+			Computed:    true,
+			Sensitive:   true,
+			Description: "The file content; null unless content_mode is \"full\" and output_dir is not set",
+		},
+		"content_present": schema.BoolAttribute{
+			Computed: true,
+			MarkdownDescription: `
+				Whether the file's content was read, telling an empty file (true,
+				with content "") apart from entries that have no content, such as
+				directories and symlinks (false). Always false with metadata_only
+				or output_dir. content is still null when content_mode is not
+				"full".
+			`,
+		},
+		"raw_content": schema.StringAttribute{
+			Computed:    true,
+			Sensitive:   true,
+			Description: "The file content as read, before normalize_line_endings and trim_trailing_newline; null unless either is set and content is not null",
+		},
+		"sha256": schema.StringAttribute{
+			Computed:    true,
+			Description: "The hex-encoded SHA-256 checksum of the file content; null when content_mode is \"none\"",
+		},
+		"mod_time": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file modification time",
+		},
+		"mode": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The file mode",
+		},
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file name",
+		},
+		"size": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The file size",
+		},
+		"uid": schema.Int32Attribute{
+			Computed:    true,
+			Description: "The file owner UID",
+		},
+		"gid": schema.Int32Attribute{
+			Computed:    true,
+			Description: "The file owner GID",
+		},
+		"type": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file type",
+		},
+		"hardlink_target": schema.StringAttribute{
+			Computed:    true,
+			Description: "The name of the entry a hardlink points to, whose content it shares; null for other file types",
+		},
+		"xattrs": schema.MapAttribute{
+			Computed:    true,
+			Description: "The file's extended attributes (e.g. security.selinux), keyed by name",
+			ElementType: types.StringType,
+		},
+		"local_path": schema.StringAttribute{
+			Computed:    true,
+			Description: "Where the file was written under output_dir; null unless output_dir is set and the file was written",
+		},
+	}
+}
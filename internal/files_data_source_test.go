@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"archive/tar"
+	"testing"
+)
+
+func TestMergePathFiles(t *testing.T) {
+	entry := func(name string, index int) *FileInfo {
+		return &FileInfo{Header: &tar.Header{Name: name}, Index: index}
+	}
+
+	results := []map[string]*FileInfo{
+		{
+			"etc/":         entry("etc/", 0),
+			"etc/hosts":    entry("etc/hosts", 1),
+			"etc/passwd":   entry("etc/passwd", 2),
+			"etc/hostname": entry("etc/hostname", 3),
+		},
+		{
+			"etc/passwd": entry("etc/passwd", 0),
+		},
+		{
+			"var/":      entry("var/", 0),
+			"var/log":   entry("var/log", 1),
+			"etc/hosts": entry("etc/hosts", 2),
+		},
+	}
+	first := results[0]["etc/passwd"]
+
+	files := mergePathFiles(results)
+
+	want := map[string]int{
+		"etc/":         0,
+		"etc/hosts":    1,
+		"etc/passwd":   2,
+		"etc/hostname": 3,
+		"var/":         4,
+		"var/log":      5,
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %d", len(want), len(files))
+	}
+	for name, index := range want {
+		if files[name] == nil || files[name].Index != index {
+			t.Errorf("expected %s at index %d, got %+v", name, index, files[name])
+		}
+	}
+	if files["etc/passwd"] != first {
+		t.Errorf("expected the entry read for the first path to be kept")
+	}
+}
@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ImageBuildResource struct {
+	DockerClient *client.Client
+}
+
+type ImageBuildResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Context         types.String `tfsdk:"context"`
+	Dockerfile      types.String `tfsdk:"dockerfile"`
+	BuildArgs       types.Map    `tfsdk:"build_args"`
+	Target          types.String `tfsdk:"target"`
+	Labels          types.Map    `tfsdk:"labels"`
+	Tags            types.List   `tfsdk:"tags"`
+	RemoveOnDestroy types.Bool   `tfsdk:"remove_on_destroy"`
+}
+
+func NewImageBuildResource() resource.Resource {
+	return &ImageBuildResource{}
+}
+
+func (r *ImageBuildResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_build"
+}
+
+func (r *ImageBuildResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Build a docker image from a local build context and tag it.
+
+			The context directory is sent to the daemon as a tar archive. Changes
+			to the files in the context are not detected; changing any build
+			setting rebuilds the image.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"context": schema.StringAttribute{
+				Required:    true,
+				Description: "The local directory to use as the build context",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"tags": schema.ListAttribute{
+				Required:    true,
+				Description: "The tags to apply to the built image, e.g. [\"app:latest\"]",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Optional
+
+			"dockerfile": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("Dockerfile"),
+				MarkdownDescription: `
+					The path of the Dockerfile, relative to the context
+
+					Default: Dockerfile
+				`,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"build_args": schema.MapAttribute{
+				Optional:    true,
+				Description: "Build-time variables, referenced by ARG instructions",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"target": schema.StringAttribute{
+				Optional:    true,
+				Description: "The build stage to build in a multi-stage Dockerfile",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels to set on the built image",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"remove_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to remove the image from the daemon when the resource is destroyed",
+			},
+
+			// Computed
+
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the built image",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ImageBuildResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.DockerClient = config.DockerClient
+}
+
+func (r *ImageBuildResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ImageBuildResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	options := build.ImageBuildOptions{
+		Dockerfile: data.Dockerfile.ValueString(),
+		Target:     data.Target.ValueString(),
+		Remove:     true,
+	}
+
+	var buildArgs map[string]string
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &options.Tags, false)...)
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &options.Labels, false)...)
+	resp.Diagnostics.Append(data.BuildArgs.ElementsAs(ctx, &buildArgs, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	options.BuildArgs = make(map[string]*string, len(buildArgs))
+	for key, value := range buildArgs {
+		options.BuildArgs[key] = &value
+	}
+
+	if info, err := os.Stat(data.Context.ValueString()); err != nil || !info.IsDir() {
+		resp.Diagnostics.AddError(
+			"Invalid Build Context",
+			fmt.Sprintf("The build context %q must be an existing directory", data.Context.ValueString()),
+		)
+		return
+	}
+
+	// the context is streamed to the daemon as it is archived
+	buildContext, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(createTarFromDirectory(data.Context.ValueString(), writer))
+	}()
+	defer buildContext.Close()
+
+	imageID, err := buildImage(ctx, r.DockerClient, buildContext, options)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Build Image",
+			fmt.Sprintf("Error building image from context %q: %v", data.Context.ValueString(), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(imageID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ImageBuildResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ImageBuildResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.DockerClient.ImageInspect(ctx, data.ID.ValueString())
+	if cerrdefs.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Image",
+			fmt.Sprintf("Error inspecting image %q: %v", data.ID.ValueString(), err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ImageBuildResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ImageBuildResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every build setting requires replacement, so only remove_on_destroy
+	// can change in place and it takes effect on delete.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ImageBuildResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ImageBuildResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.RemoveOnDestroy.ValueBool() {
+		return
+	}
+
+	_, err := r.DockerClient.ImageRemove(ctx, data.ID.ValueString(), image.RemoveOptions{Force: true, PruneChildren: true})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Unable to Remove Image",
+			fmt.Sprintf("Error removing image %q: %v", data.ID.ValueString(), err),
+		)
+		return
+	}
+}
+
+// buildImage sends the build context to the daemon and reads the streamed
+// build output until it completes, returning the ID of the built image.
+// A build step failure is returned as an error with the daemon's message.
+func buildImage(ctx context.Context, dockerClient *client.Client, buildContext io.Reader, options build.ImageBuildOptions) (string, error) {
+	response, err := dockerClient.ImageBuild(ctx, buildContext, options)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	var imageID string
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("failed to read build output: %w", err)
+		}
+
+		if msg.Error != nil {
+			return "", errors.New(msg.Error.Message)
+		}
+
+		// the image ID is sent as an aux message once the build succeeds
+		if msg.Aux != nil {
+			var result build.Result
+			if err := json.Unmarshal(*msg.Aux, &result); err == nil && result.ID != "" {
+				imageID = result.ID
+			}
+		}
+	}
+
+	if imageID == "" {
+		return "", fmt.Errorf("build completed without reporting an image ID")
+	}
+
+	return imageID, nil
+}
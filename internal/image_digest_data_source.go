@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type ImageDigestDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+}
+
+type ImageDigestDataSourceModel struct {
+	Name       types.String `tfsdk:"name"`
+	Host       types.String `tfsdk:"host"`
+	Platform   types.String `tfsdk:"platform"`
+	ID         types.String `tfsdk:"id"`
+	RepoDigest types.String `tfsdk:"repo_digest"`
+
+	OS           types.String `tfsdk:"os"`
+	Architecture types.String `tfsdk:"architecture"`
+}
+
+func NewImageDigestDataSource() datasource.DataSource {
+	return &ImageDigestDataSource{}
+}
+
+func (d *ImageDigestDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_digest"
+}
+
+func (d *ImageDigestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Resolve a locally pulled image tag to its repository digest, to pin
+			deployments to the image that was pulled.
+
+			The image must already be present on the docker daemon. Images that
+			were built locally and never pushed or pulled have no digest.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The image reference to resolve, e.g. nginx:alpine",
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"platform": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					The platform the image must be for, as os/arch or os/arch/variant
+					(e.g. linux/arm64), to catch an image pulled for the wrong
+					architecture. Daemons with API 1.49 or later inspect that platform
+					of a multi-platform image; the read fails when the image doesn't
+					match.
+				`,
+			},
+
+			// Computed
+
+			"os": schema.StringAttribute{
+				Computed:    true,
+				Description: "The operating system the image is built for",
+			},
+
+			"architecture": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CPU architecture the image is built for",
+			},
+
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The local image ID",
+			},
+
+			"repo_digest": schema.StringAttribute{
+				Computed:    true,
+				Description: "The digest reference of the image in the repository of name, e.g. nginx@sha256:...",
+			},
+		},
+	}
+}
+
+func (d *ImageDigestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+}
+
+func (d *ImageDigestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ImageDigestDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	named, err := reference.ParseNormalizedNamed(data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Image Name",
+			fmt.Sprintf("Image name validation failed for %q: %v", data.Name.ValueString(), err),
+		)
+		return
+	}
+
+	var platform *ocispec.Platform
+	if !data.Platform.IsNull() {
+		platform, err = parsePlatform(data.Platform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Platform",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	// older daemons can't select the platform, so the match is only checked below
+	var inspectOptions []client.ImageInspectOption
+	if platform != nil && dockerClient.NewVersionError(ctx, "1.49", "platform") == nil {
+		inspectOptions = append(inspectOptions, client.ImageInspectWithPlatform(platform))
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, data.Name.ValueString(), inspectOptions...)
+	if cerrdefs.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Image Not Found",
+			fmt.Sprintf("Image %q is not present on the daemon; pull it before resolving its digest", data.Name.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Inspect Image",
+			fmt.Sprintf("Error inspecting image %q: %v", data.Name.ValueString(), err),
+		)
+		return
+	}
+
+	if platform != nil && !platformMatches(platform, inspect.Os, inspect.Architecture, inspect.Variant) {
+		resp.Diagnostics.AddError(
+			"Image Platform Mismatch",
+			fmt.Sprintf("Image %q is for platform %s, not the requested %s", data.Name.ValueString(), strings.Trim(inspect.Os+"/"+inspect.Architecture+"/"+inspect.Variant, "/"), data.Platform.ValueString()),
+		)
+		return
+	}
+
+	repoDigest, err := findRepoDigest(named, inspect.RepoDigests)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Image Digest Not Found",
+			fmt.Sprintf("Error resolving the digest of image %q: %v", data.Name.ValueString(), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(inspect.ID)
+	data.RepoDigest = types.StringValue(repoDigest)
+	data.OS = types.StringValue(inspect.Os)
+	data.Architecture = types.StringValue(inspect.Architecture)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findRepoDigest returns the repo digest in the same repository as named.
+// An image tagged into several repositories has one digest per repository.
+func findRepoDigest(named reference.Named, repoDigests []string) (string, error) {
+	for _, repoDigest := range repoDigests {
+		digested, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil {
+			continue
+		}
+
+		if digested.Name() == named.Name() {
+			return repoDigest, nil
+		}
+	}
+
+	if len(repoDigests) == 0 {
+		return "", fmt.Errorf("the image has no repo digests, it was probably built locally and never pushed or pulled")
+	}
+
+	return "", fmt.Errorf("none of the image's repo digests %v are in repository %q", repoDigests, reference.FamiliarName(named))
+}
+
+// parsePlatform parses an os/arch or os/arch/variant platform.
+func parsePlatform(value string) (*ocispec.Platform, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return nil, &ValidationError{Field: "platform", Reason: fmt.Sprintf("%q is not of the form os/arch or os/arch/variant", value)}
+	}
+
+	platform := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		platform.Variant = parts[2]
+	}
+
+	return platform, nil
+}
+
+// platformMatches reports whether an image's platform is the requested one.
+// The variant is only compared when one was requested, so linux/arm64
+// matches an image for linux/arm64/v8.
+func platformMatches(platform *ocispec.Platform, os, architecture, variant string) bool {
+	if platform.OS != os || platform.Architecture != architecture {
+		return false
+	}
+
+	return platform.Variant == "" || platform.Variant == variant
+}
@@ -1,43 +1,128 @@
 package internal
 
 import (
-	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/adduc/terraform-provider-docker/internal/stdstream"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
-// Docker log format constants
+// DefaultMaxLineBytes is the number of bytes kept of each log line when max_line_bytes is unset
+const DefaultMaxLineBytes = 1024 * 1024
+
+// LogTruncatedMarker is appended to messages cut to max_line_bytes
+const LogTruncatedMarker = " [truncated]"
+
+// Long line handling modes
+const (
+	LogLongLinesTruncate = "truncate"
+	LogLongLinesSkip     = "skip"
+)
+
+// full_text prefix modes
 const (
-	// DockerLogHeaderSize is the size of the Docker log entry header
-	DockerLogHeaderSize = 8
-	// DockerLogTimestampSize is the size of the RFC3339 timestamp in Docker logs
-	DockerLogTimestampSize = 22
-	// DockerLogTimestampEnd is the position where the timestamp ends
-	DockerLogTimestampEnd = DockerLogHeaderSize + DockerLogTimestampSize // 30
-	// DockerLogMessageStart is the position where the log message starts (after timestamp + separator)
-	DockerLogMessageStart = DockerLogTimestampEnd + 9 // 39
+	LogFullTextPrefixNone      = "none"
+	LogFullTextPrefixStream    = "stream"
+	LogFullTextPrefixTimestamp = "timestamp"
+	LogFullTextPrefixBoth      = "both"
 )
 
+// logLine is a single message parsed from a docker log stream
+type logLine struct {
+	Stdout    bool
+	Stderr    bool
+	Timestamp string // empty when the stream was read without timestamps
+	Message   string
+	Truncated bool // whether the message was cut to the maximum line length
+
+	Details map[string]string // the log attributes, nil when the stream was read without details
+}
+
+// logLineAttrTypes are the attribute types of a parsed log line
+var logLineAttrTypes = map[string]attr.Type{
+	"stdout":    types.BoolType,
+	"stderr":    types.BoolType,
+	"message":   types.StringType,
+	"timestamp": types.StringType,
+}
+
+// logsContainerMetadataAttrTypes are the attribute types of the container_metadata of docker_logs
+var logsContainerMetadataAttrTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"image":      types.StringType,
+	"labels":     types.MapType{ElemType: types.StringType},
+	"started_at": types.StringType,
+}
+
+// logsLineAttrTypes are the attribute types of a docker_logs log line, which
+// extend logLineAttrTypes with the values derived from the data source options
+var logsLineAttrTypes = map[string]attr.Type{
+	"stdout":      types.BoolType,
+	"stderr":      types.BoolType,
+	"message":     types.StringType,
+	"timestamp":   types.StringType,
+	"fields":      types.MapType{ElemType: types.StringType},
+	"line_number": types.Int64Type,
+	"details":     types.MapType{ElemType: types.StringType},
+}
+
 func NewLogsDataSource() datasource.DataSource {
 	return &LogsDataSource{}
 }
 
 type LogsDataSource struct {
 	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+
+	MaxResponseBytes int64
 }
 
 type LogsDataSourceModel struct {
 	Container  types.String `tfsdk:"container"`
+	Host       types.String `tfsdk:"host"`
+	Timeout    types.Int32  `tfsdk:"timeout"`
+	Label      types.Map    `tfsdk:"label"`
 	Logs       types.List   `tfsdk:"logs"`
 	Timestamps types.Bool   `tfsdk:"timestamps"`
+	ParseJSON  types.Bool   `tfsdk:"parse_json"`
+
+	StripTimestampFromMessage types.Bool `tfsdk:"strip_timestamp_from_message"`
+
+	MaxLineBytes types.Int32  `tfsdk:"max_line_bytes"`
+	LongLines    types.String `tfsdk:"long_lines"`
+
+	SinceStart types.Bool   `tfsdk:"since_start"`
+	Since      types.String `tfsdk:"since"`
+
+	LastTimestamp types.String `tfsdk:"last_timestamp"`
+
+	FullText       types.String `tfsdk:"full_text"`
+	FullTextPrefix types.String `tfsdk:"full_text_prefix"`
+
+	Details types.Bool `tfsdk:"details"`
+
+	SkipExistenceCheck types.Bool `tfsdk:"skip_existence_check"`
+
+	WaitForRunning types.Bool  `tfsdk:"wait_for_running"`
+	WaitTimeout    types.Int32 `tfsdk:"wait_timeout"`
+
+	IncludeMetadata   types.Bool   `tfsdk:"include_metadata"`
+	ContainerMetadata types.Object `tfsdk:"container_metadata"`
 }
 
 func (d *LogsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -46,27 +131,182 @@ func (d *LogsDataSource) Metadata(ctx context.Context, req datasource.MetadataRe
 
 func (d *LogsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Retrieve the stdout and stderr logs of a docker container.
+
+			For the json-file and local log drivers the daemon reads rotated log
+			segments, including gzip-compressed ones, in addition to the current
+			log file, so history is available as far back as the driver's
+			max-file setting retains it.
+		`,
 		Attributes: map[string]schema.Attribute{
 
-			// Required
+			// Optional
 
 			"container": schema.StringAttribute{
-				Required:    true,
-				Description: "The name of the container",
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the container, resolved from label when not set",
 			},
 
-			// Optional
+			"label": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels identifying exactly one container, as an alternative to container (e.g. compose service labels)",
+				ElementType: types.StringType,
+			},
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds the whole read may take, including waiting for a free request
+					slot, on top of the provider's per-request timeout. Unset means no
+					limit.
+				`,
+			},
 
 			"timestamps": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Whether the log has timestamps",
 			},
 
+			"since_start": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to only read logs written since the container was last started, leaving out output from before a restart",
+			},
+
+			"since": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Only read logs written since this timestamp (unix or RFC3339) or
+					relative duration (e.g. 10m). Lines written at exactly since are
+					included, so reading from the last_timestamp of a previous read
+					repeats its final line. Cannot be combined with since_start.
+				`,
+			},
+
+			"strip_timestamp_from_message": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to remove the timestamp from the start of each message when
+					timestamps is set, so it only appears in the timestamp field
+
+					Default: true
+				`,
+			},
+
+			"max_line_bytes": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum number of bytes kept of each log line, including the
+					timestamp, so a single pathological line can't exhaust memory or
+					bloat state. Must be at least 64.
+
+					Default: 1048576 (1MB)
+				`,
+			},
+
+			"long_lines": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					How to handle lines longer than max_line_bytes: "truncate" keeps the
+					start of the line followed by " [truncated]", "skip" drops the line
+
+					Default: "truncate"
+				`,
+			},
+
+			"parse_json": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to decode each message as a JSON object into fields",
+			},
+
+			"full_text_prefix": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					What to prefix each line of full_text with: "none", "stream" (stdout
+					or stderr), "timestamp", or "both" for the timestamp followed by the
+					stream, separated from the message by spaces. Timestamps are only
+					known when timestamps is set.
+
+					Default: "none"
+				`,
+			},
+
+			"details": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to read the extra attributes the log driver records with each line (e.g. the labels and env set in its log-opts) into details",
+			},
+
+			"skip_existence_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip inspecting the container up front, saving a request at the cost of a less specific error when it is missing",
+			},
+
+			"wait_for_running": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to wait until the container is running before reading its logs, e.g. while it is still being started during provisioning",
+			},
+
+			"wait_timeout": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					Seconds to wait for the container to be running when wait_for_running
+					is set
+
+					Default: 60 seconds
+				`,
+			},
+
+			"include_metadata": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to also inspect the container into container_metadata, e.g. to tag the logs with their image and labels",
+			},
+
 			// Computed
 
+			"container_metadata": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The container the logs were read from; null unless include_metadata is set",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:    true,
+						Description: "The container ID",
+					},
+					"image": schema.StringAttribute{
+						Computed:    true,
+						Description: "The image the container was created from, as given at creation",
+					},
+					"labels": schema.MapAttribute{
+						Computed:    true,
+						Description: "The labels of the container",
+						ElementType: types.StringType,
+					},
+					"started_at": schema.StringAttribute{
+						Computed:    true,
+						Description: "The time the container was last started, in RFC3339 format; empty if it never started",
+					},
+				},
+			},
+
+			"last_timestamp": schema.StringAttribute{
+				Computed:    true,
+				Description: "The timestamp of the final line in the log stream, to pass as since on the next read; null unless timestamps is set and the container has written output",
+			},
+
+			"full_text": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The messages of logs joined into one string, one line each, prefixed as set by full_text_prefix",
+			},
+
 			"logs": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "The logs of the container",
+				Description: "The logs of the container; an empty list, never null, when it has written no output",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"stdout": schema.BoolAttribute{
@@ -85,6 +325,20 @@ func (d *LogsDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 							Required:    true,
 							Description: "The log timestamp",
 						},
+						"fields": schema.MapAttribute{
+							Computed:    true,
+							Description: "The top-level fields of a JSON object message, with non-string values as JSON; null unless parse_json is set and the message is a JSON object",
+							ElementType: types.StringType,
+						},
+						"details": schema.MapAttribute{
+							Computed:    true,
+							Description: "The extra attributes recorded by the log driver; null unless details is set",
+							ElementType: types.StringType,
+						},
+						"line_number": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The 1-based position of the line in the log stream, counted before lines are dropped so gaps show where lines were skipped",
+						},
 					},
 				},
 			},
@@ -108,6 +362,9 @@ func (d *LogsDataSource) Configure(ctx context.Context, req datasource.Configure
 	}
 
 	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+	d.MaxResponseBytes = config.MaxResponseBytes
 }
 
 func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -124,135 +381,573 @@ func (d *LogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		data.Timestamps = types.BoolValue(true)
 	}
 
-	// Validate container name
-	if err := validateContainerName(data.Container.ValueString()); err != nil {
+	// strip_timestamp_from_message defaults to true
+	if data.StripTimestampFromMessage.IsNull() {
+		data.StripTimestampFromMessage = types.BoolValue(true)
+	}
+
+	maxLineBytes := int32(DefaultMaxLineBytes)
+	if !data.MaxLineBytes.IsNull() {
+		maxLineBytes = data.MaxLineBytes.ValueInt32()
+	}
+
+	// leave room for the timestamp prefix, which counts towards the limit
+	if maxLineBytes < 64 {
 		resp.Diagnostics.AddError(
-			"Invalid Container Name",
-			fmt.Sprintf("Container name validation failed: %v", err),
+			"Invalid Max Line Bytes",
+			fmt.Sprintf("max_line_bytes must be at least 64, got: %d", maxLineBytes),
 		)
 		return
 	}
 
-	// get container logs
+	longLines := LogLongLinesTruncate
+	if !data.LongLines.IsNull() {
+		longLines = data.LongLines.ValueString()
+	}
 
-	options := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Timestamps: data.Timestamps.ValueBool(),
+	if longLines != LogLongLinesTruncate && longLines != LogLongLinesSkip {
+		resp.Diagnostics.AddError(
+			"Invalid Long Line Handling",
+			fmt.Sprintf("long_lines must be %q or %q, got: %q", LogLongLinesTruncate, LogLongLinesSkip, longLines),
+		)
+		return
+	}
+
+	fullTextPrefix := LogFullTextPrefixNone
+	if !data.FullTextPrefix.IsNull() {
+		fullTextPrefix = data.FullTextPrefix.ValueString()
+	}
+
+	if !slices.Contains([]string{LogFullTextPrefixNone, LogFullTextPrefixStream, LogFullTextPrefixTimestamp, LogFullTextPrefixBoth}, fullTextPrefix) {
+		resp.Diagnostics.AddError(
+			"Invalid Full Text Prefix",
+			fmt.Sprintf("full_text_prefix must be %q, %q, %q or %q, got: %q", LogFullTextPrefixNone, LogFullTextPrefixStream, LogFullTextPrefixTimestamp, LogFullTextPrefixBoth, fullTextPrefix),
+		)
+		return
+	}
+
+	if !data.Since.IsNull() && data.SinceStart.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Conflicting Log Read Options",
+			"since and since_start cannot be combined",
+		)
+		return
 	}
 
-	logs, err := d.DockerClient.ContainerLogs(ctx, data.Container.ValueString(), options)
+	ref := ContainerRef{Name: data.Container.ValueString()}
+	resp.Diagnostics.Append(data.Label.ElementsAs(ctx, &ref.Labels, false)...)
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := ref.Validate(); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Container Selector",
+			err.Error(),
+		)
+		return
+	}
+
+	waitTimeout := int32(60)
+	if !data.WaitTimeout.IsNull() {
+		waitTimeout = data.WaitTimeout.ValueInt32()
+	}
+
+	if waitTimeout < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Wait Timeout",
+			fmt.Sprintf("wait_timeout must be at least 1, got: %d", waitTimeout),
+		)
+		return
+	}
+
+	if !data.Timeout.IsNull() {
+		if data.Timeout.ValueInt32() < 1 {
+			resp.Diagnostics.AddError(
+				"Invalid Timeout",
+				fmt.Sprintf("timeout must be at least 1, got: %d", data.Timeout.ValueInt32()),
+			)
+			return
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(data.Timeout.ValueInt32())*time.Second)
+		defer cancel()
+
+		// added alongside the error of whichever request was cut short
+		defer func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				resp.Diagnostics.AddError(
+					"Read Timed Out",
+					fmt.Sprintf("docker_logs did not finish reading from container %q within the %d second timeout", data.Container.ValueString(), data.Timeout.ValueInt32()),
+				)
+			}
+		}()
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Read Container Logs",
-			fmt.Sprintf("Error reading logs for container %q: %v", data.Container.ValueString(), err),
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
 		)
 		return
 	}
 	defer func() {
-		if closeErr := logs.Close(); closeErr != nil {
+		if closeErr := closeClient(); closeErr != nil {
 			resp.Diagnostics.AddWarning(
 				"Resource Cleanup Warning",
-				fmt.Sprintf("Failed to close log stream for container %q: %v", data.Container.ValueString(), closeErr),
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
 			)
 		}
 	}()
 
-	// parse logs
+	// resolve label selector
+
+	name, err := ResolveContainer(ctx, dockerClient, ref)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Resolve Container",
+			formatError("find", fmt.Sprintf("a container with labels %v", ref.Labels), "", "", err),
+		)
+		return
+	}
+
+	data.Container = types.StringValue(name)
 
-	var logLines []attr.Value
-	scanner := bufio.NewScanner(logs)
+	// containers resolved from labels or waited for are already known to exist
+	if !data.SkipExistenceCheck.ValueBool() && data.Label.IsNull() && !data.WaitForRunning.ValueBool() {
+		err := checkContainerExists(ctx, dockerClient, data.Container.ValueString())
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		logLine, err := processLogLine(line, options)
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Container Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+	}
+
+	if data.WaitForRunning.ValueBool() {
+		err := waitForRunning(ctx, dockerClient, data.Container.ValueString(), time.Duration(waitTimeout)*time.Second)
+		err = wrapNotFound("container", data.Container.ValueString(), err)
+
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Container Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Container Not Running",
+				formatError("wait for", fmt.Sprintf("container %q to be running", data.Container.ValueString()), "", "", err),
+			)
+			return
+		}
+	}
+
+	// get container logs
+
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: data.Timestamps.ValueBool(),
+		Details:    data.Details.ValueBool(),
+		Since:      data.Since.ValueString(),
+	}
+
+	data.ContainerMetadata = types.ObjectNull(logsContainerMetadataAttrTypes)
+
+	if data.SinceStart.ValueBool() || data.IncludeMetadata.ValueBool() {
+		inspect, err := dockerClient.ContainerInspect(ctx, data.Container.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Unable to Process Log Line",
-				fmt.Sprintf("Error processing log line for container %q: %v", data.Container.ValueString(), err),
+				"Unable to Inspect Container",
+				formatError("inspect", fmt.Sprintf("container %q", data.Container.ValueString()), "", "", err),
 			)
 			return
 		}
-		logLines = append(logLines, logLine)
+
+		// a container that never started reports the zero time, and has no logs to skip
+		startedAt := ""
+		if inspect.State != nil {
+			if t, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil && !t.IsZero() {
+				startedAt = inspect.State.StartedAt
+			}
+		}
+
+		if data.SinceStart.ValueBool() && startedAt != "" {
+			options.Since = startedAt
+		}
+
+		if data.IncludeMetadata.ValueBool() {
+			labels := map[string]attr.Value{}
+			image := ""
+			if inspect.Config != nil {
+				for key, value := range inspect.Config.Labels {
+					labels[key] = types.StringValue(value)
+				}
+				image = inspect.Config.Image
+			}
+
+			data.ContainerMetadata = types.ObjectValueMust(logsContainerMetadataAttrTypes, map[string]attr.Value{
+				"id":         types.StringValue(inspect.ID),
+				"image":      types.StringValue(image),
+				"labels":     types.MapValueMust(types.StringType, labels),
+				"started_at": types.StringValue(startedAt),
+			})
+		}
 	}
 
-	if err := scanner.Err(); err != nil {
+	// get and parse logs
+
+	logLines, err := readContainerLogs(ctx, dockerClient, data.Container.ValueString(), options, int(maxLineBytes), d.MaxResponseBytes)
+	if ctx.Err() != nil {
+		resp.Diagnostics.AddError(
+			"Container Log Read Cancelled",
+			formatError("read", fmt.Sprintf("logs of container %q", data.Container.ValueString()), "", "the read was interrupted", ctx.Err()),
+		)
+		return
+	}
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		resp.Diagnostics.AddError(
+			"Container Not Found",
+			err.Error(),
+		)
+		return
+	}
+
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		resp.Diagnostics.AddError(
+			"Daemon Response Too Large",
+			formatError("read", fmt.Sprintf("logs of container %q", data.Container.ValueString()), "", "", err),
+		)
+		return
+	}
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Container Logs",
-			fmt.Sprintf("Error reading logs for container %q: %v", data.Container.ValueString(), err),
+			formatError("read", fmt.Sprintf("logs of container %q", data.Container.ValueString()), "", "", err),
 		)
 		return
 	}
 
 	// set logs
 
+	// taken before long lines are skipped, so the cursor covers the whole stream
+	data.LastTimestamp = types.StringNull()
+	if len(logLines) > 0 && logLines[len(logLines)-1].Timestamp != "" {
+		data.LastTimestamp = types.StringValue(logLines[len(logLines)-1].Timestamp)
+	}
+
+	logValues := logsLineValues(logLines, longLines, data.StripTimestampFromMessage.ValueBool(), data.ParseJSON.ValueBool())
+
 	data.Logs = types.ListValueMust(
-		types.ObjectType{
-			AttrTypes: map[string]attr.Type{
-				"stdout":    types.BoolType,
-				"stderr":    types.BoolType,
-				"message":   types.StringType,
-				"timestamp": types.StringType,
-			},
-		},
-		logLines,
+		types.ObjectType{AttrTypes: logsLineAttrTypes},
+		logValues,
 	)
 
+	data.FullText = types.StringValue(logsFullText(logLines, longLines, fullTextPrefix))
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func processLogLine(line string, logOptions container.LogsOptions) (attr.Value, error) {
-	// first byte in line is the stream type
-	// 0: stdin
-	// 1: stdout
-	// 2: stderr
-	if len(line) == 0 {
-		return nil, fmt.Errorf("empty log line")
-	}
-	
-	streamType := line[0]
-	stdout, stderr := false, false
-
-	switch streamType {
-	case '\x00': // stdin
-		return nil, fmt.Errorf("unexpected stdin log line")
-	case '\x01': // stdout
-		stdout = true
-	case '\x02': // stderr
-		stderr = true
+// logsFullText joins the messages of the log lines docker_logs keeps into one
+// newline-separated string, each prefixed as selected by prefix, one of the
+// LogFullTextPrefix modes.
+func logsFullText(logLines []logLine, longLines string, prefix string) string {
+	var text strings.Builder
+	for _, line := range logLines {
+		if line.Truncated && longLines == LogLongLinesSkip {
+			continue
+		}
+
+		if (prefix == LogFullTextPrefixTimestamp || prefix == LogFullTextPrefixBoth) && line.Timestamp != "" {
+			text.WriteString(line.Timestamp + " ")
+		}
+
+		if prefix == LogFullTextPrefixStream || prefix == LogFullTextPrefixBoth {
+			stream := "stdout"
+			if line.Stderr {
+				stream = "stderr"
+			}
+			text.WriteString(stream + " ")
+		}
+
+		text.WriteString(line.Message + "\n")
+	}
+
+	return text.String()
+}
+
+// logsLineValues converts parsed log lines into docker_logs log line objects,
+// matching logsLineAttrTypes. Lines cut to the maximum line length are dropped
+// when longLines is "skip".
+func logsLineValues(logLines []logLine, longLines string, stripTimestamp, parseJSON bool) []attr.Value {
+	logValues := []attr.Value{}
+	for i, line := range logLines {
+		// number lines before filtering so they keep their stream position
+		lineNumber := int64(i + 1)
+
+		if line.Truncated && longLines == LogLongLinesSkip {
+			continue
+		}
+
+		if !stripTimestamp && line.Timestamp != "" {
+			line.Message = line.Timestamp + " " + line.Message
+		}
+
+		values := line.attrValues()
+
+		values["fields"] = types.MapNull(types.StringType)
+		if parseJSON {
+			values["fields"] = parseJSONFields(line.Message)
+		}
+
+		values["line_number"] = types.Int64Value(lineNumber)
+
+		values["details"] = types.MapNull(types.StringType)
+		if line.Details != nil {
+			details := map[string]attr.Value{}
+			for key, value := range line.Details {
+				details[key] = types.StringValue(value)
+			}
+			values["details"] = types.MapValueMust(types.StringType, details)
+		}
+
+		logValues = append(logValues, types.ObjectValueMust(logsLineAttrTypes, values))
+	}
+
+	return logValues
+}
+
+// readContainerLogs reads and parses the logs of a single container with
+// readLogLines. A failed read is explained by the container's log driver
+// when the daemon can't read its logs back (see logDriverDetails).
+// maxResponseBytes limits the size of the log stream; zero means no limit.
+func readContainerLogs(ctx context.Context, dockerClient *client.Client, containerName string, options container.LogsOptions, maxLineBytes int, maxResponseBytes int64) ([]logLine, error) {
+	logs, err := dockerClient.ContainerLogs(ctx, containerName, options)
+	if err != nil {
+		err = wrapNotFound("container", containerName, err)
+
+		if details := logDriverDetails(ctx, dockerClient, containerName); details != "" {
+			return nil, fmt.Errorf("%w (%s)", err, details)
+		}
+		return nil, err
+	}
+	defer logs.Close()
+
+	return readLogLines(ctx, newLimitedReader(logs, maxResponseBytes), options, maxLineBytes)
+}
+
+// readLogLines demultiplexes a docker log stream read with options into log
+// lines, one per frame. Lines longer than maxLineBytes are truncated; zero
+// means no limit. Reading stops with the context's error as soon as ctx is done.
+// An empty stream, from a container that has written no output, yields an
+// empty, non-nil slice.
+func readLogLines(ctx context.Context, logs io.Reader, options container.LogsOptions, maxLineBytes int) ([]logLine, error) {
+	reader := stdstream.NewReader(newContextReader(ctx, logs))
+	reader.Timestamps = options.Timestamps
+	reader.Details = options.Details
+	reader.MaxPayloadSize = maxLineBytes
+
+	frames, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	logLines := []logLine{}
+	for _, frame := range frames {
+		line, err := processLogLine(frame)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process log line: %w", err)
+		}
+
+		if options.Details {
+			line.Details, err = parseLogDetails(frame.Details)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process log line details: %w", err)
+			}
+		}
+		logLines = append(logLines, line)
+	}
+
+	return logLines, nil
+}
+
+func processLogLine(frame stdstream.Frame) (logLine, error) {
+	var line logLine
+
+	switch frame.Stream {
+	case stdstream.Stdout:
+		line.Stdout = true
+	case stdstream.Stderr:
+		line.Stderr = true
 	default:
-		return nil, fmt.Errorf("unknown log line type: %q", streamType)
+		return line, fmt.Errorf("unexpected %s log line", frame.Stream)
+	}
+
+	// each frame holds a single log message, terminated by a newline
+	line.Message = strings.TrimSuffix(string(frame.Payload), "\n")
+	line.Message = strings.TrimSuffix(line.Message, "\r")
+	line.Timestamp = frame.Timestamp
+
+	// the daemon always sends RFC3339 timestamps, so anything else means the
+	// prefix split off was part of the message
+	if line.Timestamp != "" {
+		if _, err := time.Parse(time.RFC3339Nano, line.Timestamp); err != nil {
+			return line, fmt.Errorf("timestamp %q is not in RFC3339 format; the stream may not be timestamped, check the timestamps setting", line.Timestamp)
+		}
+	}
+
+	if frame.Truncated {
+		line.Message += LogTruncatedMarker
+		line.Truncated = true
+	}
+
+	return line, nil
+}
+
+// parseLogDetails decodes the attributes the daemon prefixes to log lines when
+// details are requested: comma-separated, query-escaped key=value pairs, which
+// are empty when the log driver records no attributes.
+func parseLogDetails(raw string) (map[string]string, error) {
+	details := map[string]string{}
+	if raw == "" {
+		return details, nil
 	}
 
-	var timestamp, message basetypes.StringValue
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("attribute %q is not a key=value pair", pair)
+		}
 
-	if logOptions.Timestamps {
-		if len(line) < DockerLogMessageStart {
-			return nil, fmt.Errorf("log line too short for timestamp parsing: need at least %d characters, got %d", DockerLogMessageStart, len(line))
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", pair, err)
 		}
-		timestamp = types.StringValue(line[DockerLogHeaderSize:DockerLogTimestampEnd])
-		message = types.StringValue(line[DockerLogMessageStart:])
-	} else {
-		if len(line) < DockerLogHeaderSize {
-			return nil, fmt.Errorf("log line too short: need at least %d characters, got %d", DockerLogHeaderSize, len(line))
+
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", pair, err)
 		}
-		message = types.StringValue(line[DockerLogHeaderSize:])
+
+		details[key] = value
 	}
 
-	return types.ObjectValueMust(
-		map[string]attr.Type{
-			"stdout":    types.BoolType,
-			"stderr":    types.BoolType,
-			"message":   types.StringType,
-			"timestamp": types.StringType,
-		},
+	return details, nil
+}
 
-		map[string]attr.Value{
-			"stdout":    types.BoolValue(stdout),
-			"stderr":    types.BoolValue(stderr),
-			"message":   message,
-			"timestamp": timestamp,
-		},
-	), nil
+// attrValues returns the attribute values of the log line, matching logLineAttrTypes.
+func (l logLine) attrValues() map[string]attr.Value {
+	timestamp := types.StringNull()
+	if l.Timestamp != "" {
+		timestamp = types.StringValue(l.Timestamp)
+	}
+
+	return map[string]attr.Value{
+		"stdout":    types.BoolValue(l.Stdout),
+		"stderr":    types.BoolValue(l.Stderr),
+		"message":   types.StringValue(l.Message),
+		"timestamp": timestamp,
+	}
+}
+
+// parseJSONFields decodes a JSON object message into its top-level fields.
+// String values are kept as is and other values are re-encoded as compact
+// JSON. It returns a null map when the message is not a JSON object.
+func parseJSONFields(message string) types.Map {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(message), &object); err != nil || object == nil {
+		return types.MapNull(types.StringType)
+	}
+
+	fields := map[string]attr.Value{}
+	for key, raw := range object {
+		var value string
+		if err := json.Unmarshal(raw, &value); err == nil {
+			fields[key] = types.StringValue(value)
+			continue
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return types.MapNull(types.StringType)
+		}
+		fields[key] = types.StringValue(compact.String())
+	}
+
+	return types.MapValueMust(types.StringType, fields)
+}
+
+// logDriverDetails explains a failed log read by the container's log driver
+// when the driver keeps no logs the daemon can read back itself, and returns
+// "" otherwise. Drivers that can be read from all produce the same stream,
+// whatever format they store logs in, so only reads fail for the others.
+func logDriverDetails(ctx context.Context, dockerClient *client.Client, containerName string) string {
+	inspect, err := dockerClient.ContainerInspect(ctx, containerName)
+	if err != nil || inspect.HostConfig == nil {
+		return ""
+	}
+
+	switch driver := inspect.HostConfig.LogConfig.Type; driver {
+	case "json-file", "local", "journald":
+		return ""
+	case "none":
+		return `the container uses the "none" log driver, which keeps no logs`
+	default:
+		return fmt.Sprintf("the container uses the %q log driver, whose logs can only be read while the daemon's dual logging cache is enabled", driver)
+	}
+}
+
+// waitForRunning inspects the container with exponential backoff until it is
+// running, giving up after timeout with the last state it was seen in.
+func waitForRunning(ctx context.Context, dockerClient *client.Client, containerName string, timeout time.Duration) error {
+	var state *container.State
+
+	err := PollUntil(ctx, WaitInitialInterval, WaitMaxInterval, timeout, func() (bool, error) {
+		inspect, err := dockerClient.ContainerInspect(ctx, containerName)
+		if err != nil {
+			return false, err
+		}
+
+		state = inspect.State
+		return state != nil && state.Running, nil
+	})
+
+	var timedOut *WaitTimeoutError
+	if errors.As(err, &timedOut) {
+		if state == nil {
+			return fmt.Errorf("%w with no state reported", err)
+		}
+		return fmt.Errorf("%w; last state %q, exit code %d", err, state.Status, state.ExitCode)
+	}
+
+	return err
 }
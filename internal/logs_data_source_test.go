@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adduc/terraform-provider-docker/internal/stdstream"
+	"github.com/docker/docker/api/types/container"
+)
+
+// logFrame returns a multiplexed log frame carrying payload on stream.
+func logFrame(stream stdstream.Stream, payload string) []byte {
+	header := make([]byte, stdstream.HeaderSize)
+	header[0] = byte(stream)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+// logsHandler serves the inspect and logs endpoints of container web, which
+// uses the given log driver. A nil stream makes the logs endpoint fail the
+// way it does for drivers the daemon can't read back.
+func logsHandler(t *testing.T, driver string, stream []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.44/containers/web/json":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(container.InspectResponse{
+				ContainerJSONBase: &container.ContainerJSONBase{
+					ID:         "0123456789ab",
+					Name:       "/web",
+					State:      &container.State{Status: container.StateRunning, Running: true},
+					HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: driver}},
+				},
+				Config: &container.Config{Image: "nginx:latest"},
+			})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.44/containers/web/logs":
+			if stream == nil {
+				writeDaemonError(w, http.StatusNotImplemented, `configured logging driver does not support reading`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			w.Write(stream)
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			writeDaemonError(w, http.StatusNotImplemented, "unexpected request")
+		}
+	})
+}
+
+// localDriverStream is the log stream of a container using the local driver,
+// configured to record its "app" label. The driver stores logs as
+// protobuf-framed, compressed entries, but the daemon decodes them and sends
+// the same multiplexed stream as for json-file.
+var localDriverStream = strings.Join([]string{
+	string(logFrame(stdstream.Stdout, "2024-05-01T10:00:00.000000001Z app=web listening on :8080\n")),
+	string(logFrame(stdstream.Stderr, "2024-05-01T10:00:01.5Z app=web warning: cache cold\r\n")),
+	string(logFrame(stdstream.Stdout, "2024-05-01T10:00:02Z app=web GET /health 200\n")),
+}, "")
+
+func TestReadContainerLogsLocalDriver(t *testing.T) {
+	dockerClient := newTestClient(t, logsHandler(t, "local", []byte(localDriverStream)))
+
+	options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: true, Details: true}
+
+	lines, err := readContainerLogs(context.Background(), dockerClient, "web", options, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []logLine{
+		{Stdout: true, Timestamp: "2024-05-01T10:00:00.000000001Z", Message: "listening on :8080"},
+		{Stderr: true, Timestamp: "2024-05-01T10:00:01.5Z", Message: "warning: cache cold"},
+		{Stdout: true, Timestamp: "2024-05-01T10:00:02Z", Message: "GET /health 200"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %+v", len(want), len(lines), lines)
+	}
+	for i, line := range lines {
+		if line.Stdout != want[i].Stdout || line.Stderr != want[i].Stderr ||
+			line.Timestamp != want[i].Timestamp || line.Message != want[i].Message {
+			t.Errorf("line %d: got %+v, want %+v", i, line, want[i])
+		}
+		if line.Details["app"] != "web" {
+			t.Errorf("line %d: expected the app detail, got %v", i, line.Details)
+		}
+	}
+}
+
+func TestLogDriverDetails(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{driver: "json-file"},
+		{driver: "local"},
+		{driver: "journald"},
+		{driver: "none", want: `"none" log driver, which keeps no logs`},
+		{driver: "syslog", want: `"syslog" log driver, whose logs can only be read while the daemon's dual logging cache is enabled`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			dockerClient := newTestClient(t, logsHandler(t, tt.driver, nil))
+
+			got := logDriverDetails(context.Background(), dockerClient, "web")
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadContainerLogsUnreadableDriver(t *testing.T) {
+	dockerClient := newTestClient(t, logsHandler(t, "syslog", nil))
+
+	_, err := readContainerLogs(context.Background(), dockerClient, "web", container.LogsOptions{ShowStdout: true}, 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "dual logging cache") {
+		t.Fatalf("expected the error to explain the log driver, got: %v", err)
+	}
+}
+
+func TestReadContainerLogsEmptyStream(t *testing.T) {
+	for _, timestamps := range []bool{false, true} {
+		dockerClient := newTestClient(t, logsHandler(t, "json-file", []byte{}))
+
+		options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: timestamps}
+
+		lines, err := readContainerLogs(context.Background(), dockerClient, "web", options, 0, 0)
+		if err != nil {
+			t.Fatalf("timestamps %t: unexpected error: %v", timestamps, err)
+		}
+		if lines == nil || len(lines) != 0 {
+			t.Errorf("timestamps %t: expected an empty, non-nil slice, got %#v", timestamps, lines)
+		}
+	}
+}
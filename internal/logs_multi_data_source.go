@@ -0,0 +1,400 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DefaultLogsMultiConcurrency is the number of containers read at once when concurrency is unset
+const DefaultLogsMultiConcurrency = 4
+
+// logsMultiElemType is the type of the per-container log lists of docker_logs_multi
+var logsMultiElemType = types.ListType{ElemType: types.ObjectType{AttrTypes: logsLineAttrTypes}}
+
+func NewLogsMultiDataSource() datasource.DataSource {
+	return &LogsMultiDataSource{}
+}
+
+type LogsMultiDataSource struct {
+	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+
+	MaxResponseBytes int64
+}
+
+type LogsMultiDataSourceModel struct {
+	Containers  types.List   `tfsdk:"containers"`
+	Host        types.String `tfsdk:"host"`
+	Logs        types.Map    `tfsdk:"logs"`
+	Timestamps  types.Bool   `tfsdk:"timestamps"`
+	ParseJSON   types.Bool   `tfsdk:"parse_json"`
+	Concurrency types.Int32  `tfsdk:"concurrency"`
+
+	SkipExistenceCheck types.Bool `tfsdk:"skip_existence_check"`
+
+	StripTimestampFromMessage types.Bool `tfsdk:"strip_timestamp_from_message"`
+
+	MaxLineBytes types.Int32  `tfsdk:"max_line_bytes"`
+	LongLines    types.String `tfsdk:"long_lines"`
+}
+
+func (d *LogsMultiDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_logs_multi"
+}
+
+func (d *LogsMultiDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+			Retrieve the stdout and stderr logs of several docker containers, such
+			as the replicas of a service, in one read.
+
+			Each container's logs are parsed the same way as docker_logs.
+		`,
+		Attributes: map[string]schema.Attribute{
+
+			// Required
+
+			"containers": schema.ListAttribute{
+				Required:    true,
+				Description: "The names of the containers",
+				ElementType: types.StringType,
+			},
+
+			// Optional
+
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Docker daemon address to read from instead of the provider's host",
+			},
+
+			"timestamps": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether the log has timestamps",
+			},
+
+			"strip_timestamp_from_message": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					Whether to remove the timestamp from the start of each message when
+					timestamps is set, so it only appears in the timestamp field
+
+					Default: true
+				`,
+			},
+
+			"max_line_bytes": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum number of bytes kept of each log line, including the
+					timestamp. Must be at least 64.
+
+					Default: 1048576 (1MB)
+				`,
+			},
+
+			"long_lines": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: `
+					How to handle lines longer than max_line_bytes: "truncate" keeps the
+					start of the line followed by " [truncated]", "skip" drops the line
+
+					Default: "truncate"
+				`,
+			},
+
+			"parse_json": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to decode each message as a JSON object into fields",
+			},
+
+			"skip_existence_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip inspecting the containers up front, saving a request at the cost of a less specific error when any is missing",
+			},
+
+			"concurrency": schema.Int32Attribute{
+				Optional: true,
+				MarkdownDescription: `
+					The maximum number of containers read at once
+
+					Default: 4
+				`,
+			},
+
+			// Computed
+
+			"logs": schema.MapAttribute{
+				Computed: true,
+				MarkdownDescription: `
+					The logs of each container keyed by container name, with the same
+					attributes as the logs of docker_logs
+				`,
+				ElementType: logsMultiElemType,
+			},
+		},
+	}
+}
+
+func (d *LogsMultiDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(ProviderConfig)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.DockerClient = config.DockerClient
+	d.ClientConfig = config.ClientConfig
+	d.Limiter = config.Limiter
+	d.MaxResponseBytes = config.MaxResponseBytes
+}
+
+func (d *LogsMultiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LogsMultiDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// timestamps defaults to true
+	if data.Timestamps.IsNull() {
+		data.Timestamps = types.BoolValue(true)
+	}
+
+	// strip_timestamp_from_message defaults to true
+	if data.StripTimestampFromMessage.IsNull() {
+		data.StripTimestampFromMessage = types.BoolValue(true)
+	}
+
+	maxLineBytes := int32(DefaultMaxLineBytes)
+	if !data.MaxLineBytes.IsNull() {
+		maxLineBytes = data.MaxLineBytes.ValueInt32()
+	}
+
+	// leave room for the timestamp prefix, which counts towards the limit
+	if maxLineBytes < 64 {
+		resp.Diagnostics.AddError(
+			"Invalid Max Line Bytes",
+			fmt.Sprintf("max_line_bytes must be at least 64, got: %d", maxLineBytes),
+		)
+		return
+	}
+
+	longLines := LogLongLinesTruncate
+	if !data.LongLines.IsNull() {
+		longLines = data.LongLines.ValueString()
+	}
+
+	if longLines != LogLongLinesTruncate && longLines != LogLongLinesSkip {
+		resp.Diagnostics.AddError(
+			"Invalid Long Line Handling",
+			fmt.Sprintf("long_lines must be %q or %q, got: %q", LogLongLinesTruncate, LogLongLinesSkip, longLines),
+		)
+		return
+	}
+
+	concurrency := int32(DefaultLogsMultiConcurrency)
+	if !data.Concurrency.IsNull() {
+		concurrency = data.Concurrency.ValueInt32()
+	}
+
+	if concurrency < 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Concurrency",
+			fmt.Sprintf("concurrency must be at least 1, got: %d", concurrency),
+		)
+		return
+	}
+
+	var containers []string
+	resp.Diagnostics.Append(data.Containers.ElementsAs(ctx, &containers, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate container names
+	seen := map[string]bool{}
+	for _, name := range containers {
+		if err := validateContainerName(name); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Container Name",
+				err.Error(),
+			)
+			return
+		}
+
+		if seen[name] {
+			resp.Diagnostics.AddError(
+				"Duplicate Container Name",
+				fmt.Sprintf("Container %q is listed more than once", name),
+			)
+			return
+		}
+		seen[name] = true
+	}
+
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	release = sync.OnceFunc(release)
+	defer release()
+
+	dockerClient, closeClient, err := clientForHost(d.DockerClient, d.ClientConfig, data.Host)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Creation Failed",
+			fmt.Sprintf("Failed to create Docker client for host %q: %v", data.Host.ValueString(), err),
+		)
+		return
+	}
+	defer func() {
+		if closeErr := closeClient(); closeErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Resource Cleanup Warning",
+				fmt.Sprintf("Failed to close Docker client for host %q: %v", data.Host.ValueString(), closeErr),
+			)
+		}
+	}()
+
+	if !data.SkipExistenceCheck.ValueBool() {
+		var errs []error
+		for _, name := range containers {
+			errs = append(errs, checkContainerExists(ctx, dockerClient, name))
+		}
+		err := errors.Join(errs...)
+
+		var notFound *NotFoundError
+		if errors.As(err, &notFound) {
+			resp.Diagnostics.AddError(
+				"Container Not Found",
+				err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Inspect Container",
+				fmt.Sprintf("Error inspecting containers: %v", err),
+			)
+			return
+		}
+	}
+
+	// get container logs
+
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: data.Timestamps.ValueBool(),
+	}
+
+	// each read takes its own slot, so the fan-out stays within the limit
+	release()
+
+	results, err := readContainersLogs(ctx, dockerClient, d.Limiter, containers, options, int(maxLineBytes), d.MaxResponseBytes, int(concurrency))
+	if ctx.Err() != nil {
+		resp.Diagnostics.AddError(
+			"Container Log Read Cancelled",
+			fmt.Sprintf("Reading logs for containers %v was interrupted: %v", containers, ctx.Err()),
+		)
+		return
+	}
+
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		resp.Diagnostics.AddError(
+			"Daemon Response Too Large",
+			fmt.Sprintf("Error reading container logs: %v", err),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Container Logs",
+			fmt.Sprintf("Error reading container logs: %v", err),
+		)
+		return
+	}
+
+	// set logs
+
+	logs := map[string]attr.Value{}
+	for i, name := range containers {
+		logValues := logsLineValues(results[i], longLines, data.StripTimestampFromMessage.ValueBool(), data.ParseJSON.ValueBool())
+
+		logs[name] = types.ListValueMust(logsMultiElemType.ElemType, logValues)
+	}
+
+	data.Logs = types.MapValueMust(logsMultiElemType, logs)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readContainersLogs reads the logs of each container with at most concurrency
+// reads in flight, returning the lines in the order of containers. Errors for
+// individual containers are joined so every failing container is reported.
+// Each read holds a slot of limiter while it runs.
+// maxResponseBytes applies to each container's log stream separately.
+func readContainersLogs(ctx context.Context, dockerClient *client.Client, limiter *RequestLimiter, containers []string, options container.LogsOptions, maxLineBytes int, maxResponseBytes int64, concurrency int) ([][]logLine, error) {
+	results := make([][]logLine, len(containers))
+	errs := make([]error, len(containers))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(containers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				release, err := limiter.Acquire(ctx)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i], errs[i] = readContainerLogs(ctx, dockerClient, containers[i], options, maxLineBytes, maxResponseBytes)
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("%s: %w", containers[i], errs[i])
+				}
+				release()
+			}
+		}()
+	}
+
+	for i := range containers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
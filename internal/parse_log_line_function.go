@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"github.com/adduc/terraform-provider-docker/internal/stdstream"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ParseLogLineFunction struct{}
+
+func NewParseLogLineFunction() function.Function {
+	return &ParseLogLineFunction{}
+}
+
+func (f *ParseLogLineFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_log_line"
+}
+
+func (f *ParseLogLineFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decode a single Docker log frame",
+		MarkdownDescription: `
+			Decodes a single base64-encoded Docker log frame (8 byte header followed
+			by the payload) into the same stdout, stderr, timestamp and message
+			attributes as the docker_logs data source.
+
+			The timestamp is null unless the payload starts with an RFC3339 timestamp
+			followed by a space, as written when logs are requested with timestamps.
+		`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "frame",
+				Description: "The base64-encoded log frame",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: logLineAttrTypes,
+		},
+	}
+}
+
+func (f *ParseLogLineFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var encoded string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &encoded))
+
+	if resp.Error != nil {
+		return
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid base64 log frame: "+err.Error())
+		return
+	}
+
+	reader := stdstream.NewReader(bytes.NewReader(raw))
+
+	frame, err := reader.Next()
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid log frame: "+err.Error())
+		return
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid log frame: expected exactly one frame")
+		return
+	}
+
+	// whether the frame was written with a timestamp isn't recorded in the
+	// frame, so it is detected from the payload instead
+	if timestamp, message, found := bytes.Cut(frame.Payload, []byte{' '}); found {
+		if _, err := time.Parse(time.RFC3339Nano, string(timestamp)); err == nil {
+			frame.Timestamp = string(timestamp)
+			frame.Payload = message
+		}
+	}
+
+	line, err := processLogLine(frame)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid log frame: "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, types.ObjectValueMust(logLineAttrTypes, line.attrValues())))
+}
@@ -2,11 +2,17 @@ package internal
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/docker/client"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,12 +24,62 @@ type Provider struct {
 }
 
 type ProviderModel struct {
-	Host    types.String `tfsdk:"host"`
-	Timeout types.Int32  `tfsdk:"timeout"`
+	Host            types.String `tfsdk:"host"`
+	Timeout         types.Int32  `tfsdk:"timeout"`
+	MaxIdleConns    types.Int32  `tfsdk:"max_idle_conns"`
+	IdleConnTimeout types.Int32  `tfsdk:"idle_conn_timeout"`
+	UserAgent       types.String `tfsdk:"user_agent"`
+	Proxy           types.String `tfsdk:"proxy"`
+
+	TLSInsecureSkipVerify types.Bool `tfsdk:"tls_insecure_skip_verify"`
+
+	MaxConcurrentRequests types.Int32 `tfsdk:"max_concurrent_requests"`
+
+	MaxResponseBytes types.Int64 `tfsdk:"max_response_bytes"`
+
+	APIVersion                types.String `tfsdk:"api_version"`
+	DisableVersionNegotiation types.Bool   `tfsdk:"disable_version_negotiation"`
+
+	HTTPHeaders types.Map `tfsdk:"http_headers"`
 }
 
 type ProviderConfig struct {
 	DockerClient *client.Client
+	ClientConfig ClientConfig
+	Limiter      *RequestLimiter
+
+	// DaemonOS is the OSType the provider's daemon reported when pinged,
+	// e.g. "linux" or "windows", used to validate container paths
+	DaemonOS string
+
+	// MaxResponseBytes caps how much of a log or archive response body is
+	// read (see newLimitedReader); zero means no limit
+	MaxResponseBytes int64
+}
+
+// ClientConfig holds the provider settings used to build a Docker client,
+// so data sources can build request-scoped clients for another daemon host.
+type ClientConfig struct {
+	Host            string
+	Timeout         time.Duration
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+	UserAgent       string
+	Proxy           string
+
+	TLSInsecureSkipVerify bool
+
+	// APIVersion pins the API version requests are made with; empty uses the
+	// client library's default
+	APIVersion string
+
+	// DisableVersionNegotiation stops the client from lowering its API
+	// version to the daemon's, which costs a ping before the first request
+	DisableVersionNegotiation bool
+
+	// HTTPHeaders are sent with every request, e.g. for an authenticating
+	// proxy in front of the daemon
+	HTTPHeaders map[string]string
 }
 
 func (p *Provider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -46,6 +102,110 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				`,
 				Optional: true,
 			},
+			"max_idle_conns": schema.Int32Attribute{
+				MarkdownDescription: `
+					The maximum number of idle connections kept open to the Docker daemon
+					for reuse across data sources. 0 falls back to Go's default of 2 per
+					daemon. The default of 6 lets most of the reads Terraform runs in
+					parallel (10 by default) reuse a connection, without holding many
+					idle sockets open on the daemon.
+
+					Default: 6
+				`,
+				Optional: true,
+			},
+			"idle_conn_timeout": schema.Int32Attribute{
+				MarkdownDescription: `
+					The number of seconds an idle connection to the Docker daemon is kept
+					open before being closed
+
+					Default: 30 seconds
+				`,
+				Optional: true,
+			},
+			"user_agent": schema.StringAttribute{
+				MarkdownDescription: `
+					The User-Agent sent with Docker API requests, to identify Terraform
+					traffic in daemon audit logs. An empty string sends no User-Agent.
+
+					Default: terraform-provider-docker/<provider version>
+				`,
+				Optional: true,
+			},
+			"proxy": schema.StringAttribute{
+				MarkdownDescription: `
+					The URL of the HTTP proxy used to reach a tcp:// daemon host. Unix
+					sockets and ssh:// hosts are always connected to directly.
+
+					Default: taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+					environment variables
+				`,
+				Optional: true,
+			},
+			"tls_insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: `
+					Whether to connect to a tcp:// daemon host over TLS without verifying
+					its certificate, e.g. for internal daemons with self-signed
+					certificates. Never applies to unix sockets or ssh:// hosts.
+
+					Default: false
+				`,
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int32Attribute{
+				MarkdownDescription: `
+					The maximum number of data sources reading from Docker daemons at
+					once across the provider, to avoid overwhelming a daemon when many
+					data sources are read in parallel. Each path of docker_files and each
+					container of docker_logs_multi read at once takes a slot of its own.
+					0 means no limit.
+
+					Default: 0
+				`,
+				Optional: true,
+			},
+			"max_response_bytes": schema.Int64Attribute{
+				MarkdownDescription: `
+					The maximum number of bytes read from a single log or file archive
+					response, so an unexpectedly large response fails the read instead
+					of exhausting memory. 0 means no limit.
+
+					Default: 0
+				`,
+				Optional: true,
+			},
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: `
+					The Docker API version to use, e.g. 1.44, instead of negotiating it
+					with the daemon
+
+					Default: negotiated with the daemon
+				`,
+				Optional: true,
+			},
+			"disable_version_negotiation": schema.BoolAttribute{
+				MarkdownDescription: `
+					Whether to skip pinging the daemon to negotiate the API version, e.g.
+					for proxies that block or stall the ping. Requests use api_version,
+					or the newest version the provider supports, which older daemons
+					reject. The daemon's reachability is then only checked by the first
+					read, and container paths are always validated as Linux paths.
+
+					Default: false
+				`,
+				Optional: true,
+			},
+			"http_headers": schema.MapAttribute{
+				MarkdownDescription: `
+					Additional HTTP headers sent with every Docker API request, e.g. an
+					auth token for a reverse proxy in front of the daemon. Headers the
+					Docker client sets itself, such as Content-Type, take precedence, and
+					User-Agent is set with user_agent.
+				`,
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
 		},
 	}
 }
@@ -64,30 +224,84 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		timeout = data.Timeout.ValueInt32()
 	}
 
-	opts := []client.Opt{
-		client.WithTimeout(time.Duration(timeout) * time.Second),
-		client.WithAPIVersionNegotiation(),
+	maxIdleConns := int32(6)
+	if !data.MaxIdleConns.IsNull() && !data.MaxIdleConns.IsUnknown() {
+		maxIdleConns = data.MaxIdleConns.ValueInt32()
+	}
+
+	idleConnTimeout := int32(30)
+	if !data.IdleConnTimeout.IsNull() && !data.IdleConnTimeout.IsUnknown() {
+		idleConnTimeout = data.IdleConnTimeout.ValueInt32()
 	}
 
-	if data.Host.ValueString() != "" {
-		helper, err := connhelper.GetConnectionHelper(data.Host.ValueString())
+	if maxIdleConns < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_idle_conns"),
+			"Invalid Max Idle Connections",
+			fmt.Sprintf("max_idle_conns cannot be negative, got: %d", maxIdleConns),
+		)
+	}
 
-		if err != nil {
+	if idleConnTimeout < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idle_conn_timeout"),
+			"Invalid Idle Connection Timeout",
+			fmt.Sprintf("idle_conn_timeout cannot be negative, got: %d", idleConnTimeout),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userAgent := "terraform-provider-docker/" + p.version
+	if !data.UserAgent.IsNull() && !data.UserAgent.IsUnknown() {
+		userAgent = data.UserAgent.ValueString()
+	}
+
+	var httpHeaders map[string]string
+	if !data.HTTPHeaders.IsNull() && !data.HTTPHeaders.IsUnknown() {
+		resp.Diagnostics.Append(data.HTTPHeaders.ElementsAs(ctx, &httpHeaders, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	for name := range httpHeaders {
+		if http.CanonicalHeaderKey(name) == "User-Agent" {
 			resp.Diagnostics.AddError(
-				"Connection Helper Error",
-				"Failed to get connection helper: "+err.Error(),
+				"Invalid HTTP Headers",
+				"http_headers cannot set User-Agent, use user_agent instead",
 			)
 			return
 		}
+	}
+
+	clientConfig := ClientConfig{
+		Host:            data.Host.ValueString(),
+		Timeout:         time.Duration(timeout) * time.Second,
+		MaxIdleConns:    int(maxIdleConns),
+		IdleConnTimeout: time.Duration(idleConnTimeout) * time.Second,
+		UserAgent:       userAgent,
+		Proxy:           data.Proxy.ValueString(),
+
+		TLSInsecureSkipVerify: data.TLSInsecureSkipVerify.ValueBool(),
+
+		APIVersion:                data.APIVersion.ValueString(),
+		DisableVersionNegotiation: data.DisableVersionNegotiation.ValueBool(),
+
+		HTTPHeaders: httpHeaders,
+	}
 
-		opts = append(
-			opts,
-			client.WithHost(helper.Host),
-			client.WithDialContext(helper.Dialer),
+	if clientConfig.TLSInsecureSkipVerify {
+		resp.Diagnostics.AddWarning(
+			"TLS Verification Disabled",
+			"tls_insecure_skip_verify is set, so the certificate of tcp:// daemon hosts is not verified and connections are open to interception.",
 		)
 	}
 
-	client, err := client.NewClientWithOpts(opts...)
+	client, err := clientConfig.NewClient()
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -97,8 +311,58 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
+	// the client is only handed to data sources once configuration succeeds
+	defer func() {
+		if resp.Diagnostics.HasError() {
+			client.Close()
+		}
+	}()
+
+	// Ping up front so an unreachable daemon or unsupported API version is
+	// reported here rather than by the first data source read. Skipping
+	// negotiation skips the ping too, leaving the daemon's OS unknown.
+	var daemonOS string
+	if !clientConfig.DisableVersionNegotiation {
+		pingCtx, cancel := context.WithTimeout(ctx, clientConfig.Timeout)
+		defer cancel()
+
+		ping, err := client.Ping(pingCtx)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Docker Daemon Unreachable",
+				fmt.Sprintf("Failed to connect to the Docker daemon at %q within %s: %v", client.DaemonHost(), clientConfig.Timeout, err),
+			)
+			return
+		}
+
+		client.NegotiateAPIVersionPing(ping)
+		daemonOS = ping.OSType
+	}
+
+	if data.MaxConcurrentRequests.ValueInt32() < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Max Concurrent Requests",
+			fmt.Sprintf("max_concurrent_requests cannot be negative, got: %d", data.MaxConcurrentRequests.ValueInt32()),
+		)
+		return
+	}
+
+	if data.MaxResponseBytes.ValueInt64() < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Max Response Bytes",
+			fmt.Sprintf("max_response_bytes cannot be negative, got: %d", data.MaxResponseBytes.ValueInt64()),
+		)
+		return
+	}
+
 	config := ProviderConfig{
 		DockerClient: client,
+		ClientConfig: clientConfig,
+		Limiter:      NewRequestLimiter(int(data.MaxConcurrentRequests.ValueInt32())),
+		DaemonOS:     daemonOS,
+
+		MaxResponseBytes: data.MaxResponseBytes.ValueInt64(),
 	}
 
 	resp.DataSourceData = config
@@ -106,7 +370,10 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 }
 
 func (p *Provider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewContainerResource,
+		NewImageBuildResource,
+	}
 }
 
 func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -114,7 +381,24 @@ func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSour
 		NewFileDataSource,
 		NewFilesDataSource,
 		NewLogsDataSource,
+		NewLogsMultiDataSource,
+		NewEventsDataSource,
+		NewEventWaitDataSource,
 		NewServerVersionDataSource,
+		NewContextDataSource,
+		NewContainerExistsDataSource,
+		NewContainerLabelsDataSource,
+		NewContainerExportDataSource,
+		NewContainerDataSource,
+		NewImageDigestDataSource,
+	}
+}
+
+func (p *Provider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewParseLogLineFunction,
+		NewSanitizePathFunction,
+		NewValidateContainerNameFunction,
 	}
 }
 
@@ -125,3 +409,110 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// NewClient builds a Docker client from the provider settings.
+func (c ClientConfig) NewClient() (*client.Client, error) {
+	// All requests go to a single daemon, so the per-host idle limit is
+	// raised to match the overall limit (net/http defaults it to 2).
+	transport := &http.Transport{
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConns,
+		IdleConnTimeout:     c.IdleConnTimeout,
+	}
+
+	opts := []client.Opt{
+		client.WithHTTPClient(&http.Client{
+			Transport:     transport,
+			CheckRedirect: client.CheckRedirect,
+		}),
+		client.WithTimeout(c.Timeout),
+		client.WithUserAgent(c.UserAgent),
+	}
+
+	if c.APIVersion != "" {
+		opts = append(opts, client.WithVersion(c.APIVersion))
+	} else if !c.DisableVersionNegotiation {
+		opts = append(opts, client.WithAPIVersionNegotiation())
+	}
+
+	if len(c.HTTPHeaders) > 0 {
+		opts = append(opts, client.WithHTTPHeaders(c.HTTPHeaders))
+	}
+
+	if c.Host == "" {
+		// the custom transport must still be configured for the default socket
+		opts = append(opts, client.WithHost(client.DefaultDockerHost))
+	} else {
+		helper, err := connhelper.GetConnectionHelper(c.Host)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connection helper: %w", err)
+		}
+
+		if helper == nil {
+			// hosts without a connection helper (tcp://, unix://) are dialed by
+			// the transport, which honors the proxy environment variables
+			opts = append(opts, client.WithHost(c.Host))
+		} else {
+			opts = append(
+				opts,
+				client.WithHost(helper.Host),
+				client.WithDialContext(helper.Dialer),
+			)
+		}
+	}
+
+	var proxyURL *url.URL
+
+	if c.Proxy != "" {
+		var err error
+		proxyURL, err = url.Parse(c.Proxy)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", c.Proxy, err)
+		}
+	}
+
+	// Applied last, as WithHost resets the transport's proxy for tcp hosts, and
+	// only to tcp hosts so unix sockets and connection helpers are unaffected.
+	opts = append(opts, func(cli *client.Client) error {
+		hostURL, err := client.ParseHostURL(cli.DaemonHost())
+		if err != nil {
+			return err
+		}
+
+		if hostURL.Scheme != "tcp" {
+			return nil
+		}
+
+		if proxyURL != nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+
+		if c.TLSInsecureSkipVerify {
+			// a TLS config also switches the client to https
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		return nil
+	})
+
+	return client.NewClientWithOpts(opts...)
+}
+
+// clientForHost returns the provider's client, or when host is set, a new
+// request-scoped client for that host built from the same settings.
+// The returned function releases the client and must always be called.
+func clientForHost(defaultClient *client.Client, config ClientConfig, host types.String) (*client.Client, func() error, error) {
+	if host.ValueString() == "" {
+		return defaultClient, func() error { return nil }, nil
+	}
+
+	config.Host = host.ValueString()
+
+	dockerClient, err := config.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dockerClient, dockerClient.Close, nil
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+type SanitizePathFunction struct{}
+
+func NewSanitizePathFunction() function.Function {
+	return &SanitizePathFunction{}
+}
+
+func (f *SanitizePathFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sanitize_path"
+}
+
+func (f *SanitizePathFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validate and clean a container file path",
+		MarkdownDescription: `
+			Cleans a path the same way the docker_file and docker_files data sources
+			do before reading it, returning the path relative to the container root.
+
+			Fails when the path is empty or traverses outside the container root,
+			so module authors can validate inputs before passing them to data sources.
+			Paths are validated as Linux container paths.
+		`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "path",
+				Description: "The path to validate",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SanitizePathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &path))
+
+	if resp.Error != nil {
+		return
+	}
+
+	sanitized, err := sanitizePath(path, "")
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, sanitized))
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/docker/docker/client"
@@ -13,11 +14,16 @@ import (
 
 type ServerVersionDataSource struct {
 	DockerClient *client.Client
+	Limiter      *RequestLimiter
 }
 
 type ServerVersionDataSourceModel struct {
 	Platform   types.Object `tfsdk:"platform"`
 	Components types.Map    `tfsdk:"components"`
+
+	ComponentsList types.List `tfsdk:"components_list"`
+
+	RawJSON types.String `tfsdk:"raw_json"`
 }
 
 func NewServerVersionDataSource() datasource.DataSource {
@@ -29,6 +35,22 @@ func (d *ServerVersionDataSource) Metadata(ctx context.Context, req datasource.M
 }
 
 func (d *ServerVersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	componentAttributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The component name",
+		},
+		"version": schema.StringAttribute{
+			Computed:    true,
+			Description: "The component version",
+		},
+		"details": schema.MapAttribute{
+			Computed:    true,
+			Description: "Additional details about the component",
+			ElementType: types.StringType,
+		},
+	}
+
 	resp.Schema = schema.Schema{
 		MarkdownDescription: `
 		Retrieves Docker server version information, including platform details,
@@ -54,26 +76,26 @@ func (d *ServerVersionDataSource) Schema(ctx context.Context, req datasource.Sch
 
 			"components": schema.MapNestedAttribute{
 				Computed:    true,
-				Description: "Components information",
+				Description: "Components information, keyed by name; when several components share a name only the last is kept",
 
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							Computed:    true,
-							Description: "The component name",
-						},
-						"version": schema.StringAttribute{
-							Computed:    true,
-							Description: "The component version",
-						},
-						"details": schema.MapAttribute{
-							Computed:    true,
-							Description: "Additional details about the component",
-							ElementType: types.StringType,
-						},
-					},
+					Attributes: componentAttributes,
+				},
+			},
+
+			"components_list": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Components information in the order reported by the daemon, including components whose names collide in components",
+
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: componentAttributes,
 				},
 			},
+
+			"raw_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full version response as JSON, for fields not mapped to attributes yet, e.g. jsondecode(data.docker_server_version.example.raw_json).ApiVersion",
+			},
 		},
 	}
 }
@@ -94,6 +116,7 @@ func (d *ServerVersionDataSource) Configure(ctx context.Context, req datasource.
 	}
 
 	d.DockerClient = config.DockerClient
+	d.Limiter = config.Limiter
 }
 
 func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -105,6 +128,16 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
+	release, err := d.Limiter.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Daemon Request Cancelled",
+			fmt.Sprintf("Cancelled while waiting for a free request slot: %v", err),
+		)
+		return
+	}
+	defer release()
+
 	version, err := d.DockerClient.ServerVersion(ctx)
 
 	if err != nil {
@@ -115,6 +148,17 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
+	rawJSON, err := json.Marshal(version)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to encode Docker server version",
+			err.Error(),
+		)
+		return
+	}
+
+	data.RawJSON = types.StringValue(string(rawJSON))
+
 	data.Platform = types.ObjectValueMust(
 		map[string]attr.Type{
 			"name": types.StringType,
@@ -131,6 +175,7 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 	}
 
 	componentAttrs := make(map[string]attr.Value)
+	componentValues := []attr.Value{}
 	for _, component := range version.Components {
 
 		details := map[string]attr.Value{}
@@ -138,7 +183,7 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 			details[key] = types.StringValue(value)
 		}
 
-		componentAttrs[component.Name] = types.ObjectValueMust(
+		componentValue := types.ObjectValueMust(
 			componentTypes,
 			map[string]attr.Value{
 				"name":    types.StringValue(component.Name),
@@ -149,6 +194,9 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 				),
 			},
 		)
+
+		componentAttrs[component.Name] = componentValue
+		componentValues = append(componentValues, componentValue)
 	}
 
 	data.Components = types.MapValueMust(
@@ -156,5 +204,10 @@ func (d *ServerVersionDataSource) Read(ctx context.Context, req datasource.ReadR
 		componentAttrs,
 	)
 
+	data.ComponentsList = types.ListValueMust(
+		types.ObjectType{AttrTypes: componentTypes},
+		componentValues,
+	)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
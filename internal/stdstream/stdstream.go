@@ -0,0 +1,174 @@
+// Package stdstream demultiplexes the framed stdout/stderr streams returned by
+// the Docker API for container logs, attach and exec when no TTY is allocated.
+//
+// Each frame starts with an 8 byte header: the stream type in the first byte,
+// three zero bytes, and the big-endian payload size in the last four bytes.
+package stdstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ansiEscape matches ANSI CSI sequences (colors, cursor movement) and OSC
+// sequences (window titles, hyperlinks) terminated by BEL or ST
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// HeaderSize is the size of the header preceding every frame payload
+const HeaderSize = 8
+
+// Stream identifies which standard stream a frame was written to.
+type Stream byte
+
+// Stream types, as written in the first byte of a frame header
+const (
+	Stdin Stream = iota
+	Stdout
+	Stderr
+	// Systemerr frames carry an error from the daemon instead of container output
+	Systemerr
+)
+
+// String returns the name of the stream, e.g. "stdout".
+func (s Stream) String() string {
+	switch s {
+	case Stdin:
+		return "stdin"
+	case Stdout:
+		return "stdout"
+	case Stderr:
+		return "stderr"
+	case Systemerr:
+		return "systemerr"
+	default:
+		return fmt.Sprintf("stream(%d)", byte(s))
+	}
+}
+
+// Frame is a single demultiplexed chunk of output.
+type Frame struct {
+	Stream    Stream // the stream the payload was written to
+	Timestamp string // the leading timestamp, only set when reading timestamped frames
+	Details   string // the comma-separated log attributes, only set when reading frames with details
+	Payload   []byte // the frame content, excluding the header, timestamp and details
+	Truncated bool   // whether the payload was cut to the reader's MaxPayloadSize
+}
+
+// Reader reads frames one at a time from a multiplexed stream.
+type Reader struct {
+	// Timestamps splits the leading "<timestamp> " prefix the daemon adds to
+	// each log frame when LogsOptions.Timestamps is set into Frame.Timestamp.
+	Timestamps bool
+
+	// Details splits the "<attributes> " prefix the daemon adds to each log
+	// frame when LogsOptions.Details is set into Frame.Details. The attributes
+	// follow the timestamp, and are empty when the log driver records none.
+	Details bool
+
+	// MaxPayloadSize limits how many bytes of each frame payload, including
+	// any timestamp prefix, are kept in memory. The rest of a larger frame is
+	// discarded and the frame is marked Truncated. Zero means no limit.
+	MaxPayloadSize int
+
+	r      io.Reader
+	header [HeaderSize]byte
+}
+
+// NewReader returns a Reader that demultiplexes frames from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next returns the next frame in the stream.
+// It returns io.EOF when the stream ends cleanly on a frame boundary,
+// and io.ErrUnexpectedEOF when it ends partway through a frame.
+func (r *Reader) Next() (Frame, error) {
+	if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+		return Frame{}, err
+	}
+
+	stream := Stream(r.header[0])
+	if stream > Systemerr {
+		return Frame{}, fmt.Errorf("unknown stream type %d in frame header", r.header[0])
+	}
+
+	size := binary.BigEndian.Uint32(r.header[4:])
+
+	kept := int64(size)
+	if r.MaxPayloadSize > 0 && kept > int64(r.MaxPayloadSize) {
+		kept = int64(r.MaxPayloadSize)
+	}
+
+	payload := make([]byte, kept)
+
+	_, err := io.ReadFull(r.r, payload)
+	if err == nil && kept < int64(size) {
+		_, err = io.CopyN(io.Discard, r.r, int64(size)-kept)
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return Frame{}, fmt.Errorf("failed to read %d byte %s frame: %w", size, stream, err)
+	}
+
+	if stream == Systemerr {
+		return Frame{}, fmt.Errorf("daemon error: %s", bytes.TrimSpace(payload))
+	}
+
+	frame := Frame{Stream: stream, Payload: payload, Truncated: kept < int64(size)}
+
+	if r.Timestamps {
+		// RFC3339Nano drops trailing zeros from the fraction, so the timestamp
+		// length varies and the payload is split on the first space instead
+		timestamp, message, found := bytes.Cut(payload, []byte{' '})
+		if !found || len(timestamp) == 0 {
+			return Frame{}, fmt.Errorf("%s frame has no timestamp prefix", stream)
+		}
+		frame.Timestamp = string(timestamp)
+		frame.Payload = message
+	}
+
+	if r.Details {
+		details, message, found := bytes.Cut(frame.Payload, []byte{' '})
+		if !found {
+			return Frame{}, fmt.Errorf("%s frame has no details prefix", stream)
+		}
+		frame.Details = string(details)
+		frame.Payload = message
+	}
+
+	return frame, nil
+}
+
+// DemuxFrames reads every frame from r until the stream ends.
+func DemuxFrames(r io.Reader) ([]Frame, error) {
+	return NewReader(r).ReadAll()
+}
+
+// ReadAll reads the remaining frames until the stream ends.
+func (r *Reader) ReadAll() ([]Frame, error) {
+	var frames []Frame
+
+	for {
+		frame, err := r.Next()
+		if err == io.EOF {
+			return frames, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+}
+
+// StripANSI removes ANSI escape sequences, such as color codes, from output
+// written by commands that assume a terminal, so it can be compared as plain
+// text.
+func StripANSI(b []byte) []byte {
+	return ansiEscape.ReplaceAll(b, nil)
+}
@@ -0,0 +1,179 @@
+package stdstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// frame returns a multiplexed frame carrying payload on stream.
+func frame(stream Stream, payload string) []byte {
+	header := make([]byte, HeaderSize)
+	header[0] = byte(stream)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestDemuxFrames(t *testing.T) {
+	stream := append(frame(Stdout, "out\n"), frame(Stderr, "err\n")...)
+
+	frames, err := DemuxFrames(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if frames[0].Stream != Stdout || string(frames[0].Payload) != "out\n" {
+		t.Errorf("unexpected first frame: %+v", frames[0])
+	}
+	if frames[1].Stream != Stderr || string(frames[1].Payload) != "err\n" {
+		t.Errorf("unexpected second frame: %+v", frames[1])
+	}
+}
+
+func TestNextTruncatedHeader(t *testing.T) {
+	stream := frame(Stdout, "out")[:5]
+
+	_, err := NewReader(bytes.NewReader(stream)).Next()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got: %v", err)
+	}
+}
+
+func TestNextShortPayload(t *testing.T) {
+	stream := frame(Stdout, "hello")
+	stream = stream[:len(stream)-2]
+
+	_, err := NewReader(bytes.NewReader(stream)).Next()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got: %v", err)
+	}
+}
+
+func TestNextSystemerr(t *testing.T) {
+	stream := append(frame(Stdout, "out"), frame(Systemerr, "container not running\n")...)
+
+	_, err := DemuxFrames(bytes.NewReader(stream))
+	if err == nil || !strings.Contains(err.Error(), "daemon error: container not running") {
+		t.Fatalf("expected a daemon error, got: %v", err)
+	}
+}
+
+func TestNextUnknownStream(t *testing.T) {
+	_, err := NewReader(bytes.NewReader(frame(Systemerr+1, "x"))).Next()
+	if err == nil || !strings.Contains(err.Error(), "unknown stream type") {
+		t.Fatalf("expected an unknown stream error, got: %v", err)
+	}
+}
+
+func TestNextTimestampsAndDetails(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps bool
+		details    bool
+		payload    string
+		want       Frame
+		wantErr    string
+	}{
+		{
+			name:       "timestamps",
+			timestamps: true,
+			payload:    "2024-01-02T03:04:05.1Z hello world",
+			want:       Frame{Stream: Stdout, Timestamp: "2024-01-02T03:04:05.1Z", Payload: []byte("hello world")},
+		},
+		{
+			name:    "details",
+			details: true,
+			payload: "app=web,env=prod hello",
+			want:    Frame{Stream: Stdout, Details: "app=web,env=prod", Payload: []byte("hello")},
+		},
+		{
+			name:       "timestamps and empty details",
+			timestamps: true,
+			details:    true,
+			payload:    "2024-01-02T03:04:05Z  hello",
+			want:       Frame{Stream: Stdout, Timestamp: "2024-01-02T03:04:05Z", Payload: []byte("hello")},
+		},
+		{
+			name:       "missing timestamp",
+			timestamps: true,
+			payload:    "hello",
+			wantErr:    "no timestamp prefix",
+		},
+		{
+			name:       "missing details",
+			timestamps: true,
+			details:    true,
+			payload:    "2024-01-02T03:04:05Z hello",
+			wantErr:    "no details prefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(frame(Stdout, tt.payload)))
+			r.Timestamps = tt.timestamps
+			r.Details = tt.details
+
+			got, err := r.Next()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Stream != tt.want.Stream || got.Timestamp != tt.want.Timestamp ||
+				got.Details != tt.want.Details || !bytes.Equal(got.Payload, tt.want.Payload) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextMaxPayloadSize(t *testing.T) {
+	stream := append(frame(Stdout, "0123456789"), frame(Stderr, "next")...)
+
+	r := NewReader(bytes.NewReader(stream))
+	r.MaxPayloadSize = 4
+
+	frames, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if string(frames[0].Payload) != "0123" || !frames[0].Truncated {
+		t.Errorf("expected a truncated \"0123\" frame, got %+v", frames[0])
+	}
+	if string(frames[1].Payload) != "next" || frames[1].Truncated {
+		t.Errorf("expected the following frame to be read whole, got %+v", frames[1])
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "plain", want: "plain"},
+		{input: "\x1b[31mred\x1b[0m", want: "red"},
+		{input: "\x1b[1;32mbold green\x1b[m text", want: "bold green text"},
+		{input: "\x1b[2K\x1b[1Gprogress", want: "progress"},
+		{input: "\x1b]0;title\x07shell", want: "shell"},
+		{input: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", want: "link"},
+	}
+
+	for _, tt := range tests {
+		if got := string(StripANSI([]byte(tt.input))); got != tt.want {
+			t.Errorf("StripANSI(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader wraps an io.Reader so that reads fail with the context's error
+// once it is done. Blocked reads on docker response bodies are already
+// interrupted by the HTTP client when the request context is cancelled; this
+// additionally stops loops that are consuming a fast, never-blocking stream.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// newContextReader returns a reader that stops returning data once ctx is done.
+func newContextReader(ctx context.Context, r io.Reader) io.Reader {
+	return &contextReader{ctx: ctx, r: r}
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// limitedReader wraps a daemon response body so that reading more than limit
+// bytes fails with a ResponseTooLargeError. Unlike io.LimitReader, which
+// would silently cut the stream short, the caller can tell an oversized
+// response apart from a complete one.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+// newLimitedReader returns a reader that fails once more than limit bytes
+// have been read from r. A limit of zero means no limit, and returns r.
+func newLimitedReader(r io.Reader, limit int64) io.Reader {
+	if limit == 0 {
+		return r
+	}
+	return &limitedReader{r: r, limit: limit}
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.read > r.limit {
+		return 0, &ResponseTooLargeError{Limit: r.limit}
+	}
+
+	// read at most one byte past the limit, enough to detect it was exceeded
+	if remaining := r.limit - r.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+
+	if r.read > r.limit {
+		return n - int(r.read-r.limit), &ResponseTooLargeError{Limit: r.limit}
+	}
+	return n, err
+}
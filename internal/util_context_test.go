@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// endlessReader is a fast, never-blocking stream that calls onRead before
+// every read.
+type endlessReader struct {
+	reads  int
+	onRead func(reads int)
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	r.reads++
+	r.onRead(r.reads)
+	clear(p)
+	return len(p), nil
+}
+
+func TestContextReaderCancelledMidRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &endlessReader{onRead: func(reads int) {
+		if reads == 3 {
+			cancel()
+		}
+	}}
+
+	n, err := io.Copy(io.Discard, newContextReader(ctx, stream))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if n == 0 {
+		t.Errorf("expected the reads before the cancellation to return data")
+	}
+	if stream.reads != 3 {
+		t.Errorf("expected reading to stop after the cancellation, got %d reads", stream.reads)
+	}
+}
+
+func TestContextReaderDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stream := &endlessReader{onRead: func(int) {}}
+
+	n, err := newContextReader(ctx, stream).Read(make([]byte, 8))
+	if n != 0 || err != ctx.Err() {
+		t.Fatalf("expected (0, %v), got (%d, %v)", ctx.Err(), n, err)
+	}
+	if stream.reads != 0 {
+		t.Errorf("expected the underlying reader not to be read, got %d reads", stream.reads)
+	}
+}
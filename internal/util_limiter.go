@@ -0,0 +1,36 @@
+package internal
+
+import "context"
+
+// RequestLimiter bounds how many data source reads talk to the daemon at once,
+// so a large graph of parallel reads can't overwhelm it. Reads that fan out
+// release their own slot and take one per request instead. A nil limiter
+// imposes no limit.
+type RequestLimiter struct {
+	slots chan struct{}
+}
+
+// NewRequestLimiter returns a limiter allowing n concurrent reads, or nil
+// (no limit) when n is zero or negative.
+func NewRequestLimiter(n int) *RequestLimiter {
+	if n <= 0 {
+		return nil
+	}
+
+	return &RequestLimiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or ctx is done. The returned function
+// releases the slot and must be called once the read is finished.
+func (l *RequestLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
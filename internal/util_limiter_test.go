@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiterBoundsConcurrency(t *testing.T) {
+	const limit = 3
+
+	limiter := NewRequestLimiter(limit)
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			defer release()
+
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got != limit {
+		t.Errorf("expected at most %d reads in flight, peak was %d", limit, got)
+	}
+}
+
+func TestRequestLimiterAcquireCancelled(t *testing.T) {
+	limiter := NewRequestLimiter(1)
+
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded while the slot is held, got: %v", err)
+	}
+}
+
+func TestRequestLimiterUnlimited(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if limiter := NewRequestLimiter(n); limiter != nil {
+			t.Errorf("NewRequestLimiter(%d): expected a nil limiter", n)
+		}
+	}
+
+	var limiter *RequestLimiter
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// ContainerRef identifies a container either by name or ID, or by labels
+// that exactly one container carries. Exactly one of the two is set.
+type ContainerRef struct {
+	Name   string            // the container name or ID, as accepted by the daemon
+	Labels map[string]string // labels identifying the container when Name is empty
+}
+
+// Validate checks that exactly one of Name or Labels is set, and that Name is
+// a valid container name, before the daemon is asked to resolve it.
+func (r ContainerRef) Validate() error {
+	if (r.Name == "") == (len(r.Labels) == 0) {
+		return &ValidationError{Field: "container selector", Reason: "exactly one of container or label must be set"}
+	}
+
+	if r.Name != "" {
+		return validateContainerName(r.Name)
+	}
+
+	return nil
+}
+
+// ResolveContainer returns the name of the container ref identifies. Names and
+// IDs are returned as they are, as the daemon resolves them itself; labels are
+// looked up with findContainerByLabels, which errors unless exactly one
+// container matches.
+func ResolveContainer(ctx context.Context, dockerClient *client.Client, ref ContainerRef) (string, error) {
+	if ref.Name != "" {
+		return ref.Name, nil
+	}
+
+	return findContainerByLabels(ctx, dockerClient, ref.Labels)
+}
+
+// findContainerByLabels returns the name of the single container, running or
+// not, carrying all of the given labels. It errors when zero or several match.
+func findContainerByLabels(ctx context.Context, dockerClient *client.Client, labels map[string]string) (string, error) {
+	args := filters.NewArgs()
+	for key, value := range labels {
+		args.Add("label", key+"="+value)
+	}
+
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(containers) {
+	case 0:
+		return "", fmt.Errorf("no container matches")
+	case 1:
+	default:
+		var ids []string
+		for _, summary := range containers {
+			ids = append(ids, summary.ID[:12])
+		}
+		return "", fmt.Errorf("%d containers match, expected exactly one: %v", len(containers), ids)
+	}
+
+	if len(containers[0].Names) == 0 {
+		return containers[0].ID, nil
+	}
+
+	return strings.TrimPrefix(containers[0].Names[0], "/"), nil
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"archive/tar"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// statAttrTypes are the attribute types of a container path stat
+var statAttrTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"size":        types.Int64Type,
+	"mode":        types.Int64Type,
+	"permissions": types.Int64Type,
+	"mtime":       types.StringType,
+	"link_target": types.StringType,
+}
+
+// statSchemaAttributes returns the schema of a container path stat.
+func statSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file name",
+		},
+		"size": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The file size",
+		},
+		"mode": schema.Int64Attribute{
+			Computed: true,
+			MarkdownDescription: `
+				The file mode as a Go os.FileMode, where the type is encoded in the
+				high bits (e.g. a 0755 directory is 2147484141). Use permissions for
+				the Unix permission bits shown by ls -l.
+			`,
+		},
+		"permissions": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The Unix permission bits, including setuid, setgid and sticky (e.g. 493 for 0755)",
+		},
+		"mtime": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file modification time",
+		},
+		"link_target": schema.StringAttribute{
+			Computed:    true,
+			Description: "The file link target",
+		},
+	}
+}
+
+// statObjectValue converts a container path stat into a stat object.
+func statObjectValue(stat container.PathStat) types.Object {
+	return types.ObjectValueMust(
+		statAttrTypes,
+		map[string]attr.Value{
+			"name":        types.StringValue(stat.Name),
+			"size":        types.Int64Value(stat.Size),
+			"mode":        types.Int64Value(int64(stat.Mode)),
+			"permissions": types.Int64Value(unixPermissions(stat.Mode)),
+			"mtime":       types.StringValue(stat.Mtime.Format(time.RFC3339)),
+			"link_target": types.StringValue(stat.LinkTarget),
+		},
+	)
+}
+
+// unixPermissions converts a Go os.FileMode into Unix permission bits.
+// os.FileMode keeps setuid, setgid and sticky in its high bits rather than
+// at their Unix positions, so they are moved back alongside the rwx bits.
+func unixPermissions(mode os.FileMode) int64 {
+	permissions := int64(mode.Perm())
+
+	if mode&os.ModeSetuid != 0 {
+		permissions |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		permissions |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		permissions |= 0o1000
+	}
+
+	return permissions
+}
+
+// xattrsMapValue returns the extended attributes of a tar entry as a map value.
+func xattrsMapValue(hdr *tar.Header) types.Map {
+	xattrs := map[string]attr.Value{}
+	for name, value := range extractXattrs(hdr) {
+		xattrs[name] = types.StringValue(value)
+	}
+
+	return types.MapValueMust(types.StringType, xattrs)
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnixPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		mode os.FileMode
+		want int64
+	}{
+		{name: "regular file", mode: 0o644, want: 0o644},
+		{name: "directory", mode: os.ModeDir | 0o755, want: 0o755},
+		{name: "setuid", mode: os.ModeSetuid | 0o755, want: 0o4755},
+		{name: "setgid directory", mode: os.ModeDir | os.ModeSetgid | 0o775, want: 0o2775},
+		{name: "sticky directory", mode: os.ModeDir | os.ModeSticky | 0o777, want: 0o1777},
+		{name: "all special bits", mode: os.ModeSetuid | os.ModeSetgid | os.ModeSticky | 0o700, want: 0o7700},
+		{name: "symlink", mode: os.ModeSymlink | 0o777, want: 0o777},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unixPermissions(tt.mode); got != tt.want {
+				t.Errorf("unixPermissions(%v) = %#o, want %#o", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatObjectValue(t *testing.T) {
+	stat := container.PathStat{
+		Name:       "bin",
+		Size:       4096,
+		Mode:       os.ModeDir | os.ModeSetgid | 0o755,
+		Mtime:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		LinkTarget: "",
+	}
+
+	attrs := statObjectValue(stat).Attributes()
+
+	if got := attrs["mode"].(types.Int64).ValueInt64(); got != int64(stat.Mode) {
+		t.Errorf("expected mode to be the os.FileMode %d, got %d", int64(stat.Mode), got)
+	}
+	if got := attrs["permissions"].(types.Int64).ValueInt64(); got != 0o2755 {
+		t.Errorf("expected permissions %#o, got %#o", 0o2755, got)
+	}
+	if got := attrs["mtime"].(types.String).ValueString(); got != "2024-05-01T10:00:00Z" {
+		t.Errorf("expected mtime in RFC3339, got %q", got)
+	}
+	if got := attrs["name"].(types.String).ValueString(); got != "bin" {
+		t.Errorf("expected name %q, got %q", "bin", got)
+	}
+}
@@ -5,32 +5,93 @@ package internal
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 )
 
+// paxXattrPrefix is the PAX record prefix under which tar stores extended attributes
+const paxXattrPrefix = "SCHILY.xattr."
+
+// PAX records giving the version of a GNU sparse file entry
+const (
+	paxGNUSparseMajor = "GNU.sparse.major"
+	paxGNUSparseMinor = "GNU.sparse.minor"
+)
+
 // File size limits
 const (
 	// MaxFileSize is the maximum size of a single file that can be extracted (10MB)
 	MaxFileSize = 10 * 1024 * 1024
+
+	// MaxArchiveSize is the maximum size of a raw tar stream that can be captured (10MB)
+	MaxArchiveSize = 10 * 1024 * 1024
+
+	// MaxReadBufferSize is the most memory preallocated for a file's content
+	// from its header size (1MB); larger files grow the buffer as they are read
+	MaxReadBufferSize = 1024 * 1024
 )
 
-// formatError creates a standardized error message with context.
-func formatError(operation, resource, details string, err error) string {
-	if err != nil {
-		return fmt.Sprintf("Failed to %s %s: %s (%v)", operation, resource, details, err)
+// TarContent selects what extractFileFromTar keeps of a regular file's content
+type TarContent int
+
+const (
+	// TarContentFull reads the content into memory, up to MaxFileSize
+	TarContentFull TarContent = iota
+
+	// TarContentChecksum streams the content through a SHA-256 hasher and
+	// discards it, so files of any size can be checksummed
+	TarContentChecksum
+
+	// TarContentNone skips the content as it is streamed
+	TarContentNone
+)
+
+// DaemonOSWindows is the OSType a daemon running Windows containers reports
+// when pinged; paths in its containers use drive letters and backslashes
+const DaemonOSWindows = "windows"
+
+// formatError creates a standardized error message with context, e.g.
+// `Failed to read file "/etc/hosts" in container "web": details (err)`.
+// The container is left out when empty, as are details when the error
+// describes the failure on its own.
+func formatError(operation, resource, container, details string, err error) string {
+	message := fmt.Sprintf("Failed to %s %s", operation, resource)
+	if container != "" {
+		message += fmt.Sprintf(" in container %q", container)
 	}
-	return fmt.Sprintf("Failed to %s %s: %s", operation, resource, details)
+
+	switch {
+	case err != nil && details == "":
+		return fmt.Sprintf("%s: %v", message, err)
+	case err != nil:
+		return fmt.Sprintf("%s: %s (%v)", message, details, err)
+	}
+	return fmt.Sprintf("%s: %s", message, details)
 }
 
 // sanitizePath validates and cleans a file path to prevent path traversal attacks.
 // It rejects paths containing ".." components and ensures the path is within bounds.
-func sanitizePath(path string) (string, error) {
+// Paths in containers of a Windows daemon (see daemonOS) are handled by
+// sanitizeWindowsPath; all others are treated as Unix paths.
+func sanitizePath(path string, daemonOS string) (string, error) {
 	if path == "" {
-		return "", fmt.Errorf("path cannot be empty")
+		return "", &ValidationError{Field: "path", Reason: "cannot be empty"}
+	}
+
+	if daemonOS == DaemonOSWindows {
+		return sanitizeWindowsPath(path)
 	}
 
 	// Clean the path to resolve any . and .. elements
@@ -38,7 +99,7 @@ func sanitizePath(path string) (string, error) {
 	
 	// Check for path traversal attempts
 	if strings.Contains(cleaned, "..") || strings.HasPrefix(cleaned, "../") {
-		return "", fmt.Errorf("path traversal detected: %s", path)
+		return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("path traversal detected in %q", path)}
 	}
 	
 	// Ensure the path doesn't start with / to avoid absolute paths
@@ -47,11 +108,142 @@ func sanitizePath(path string) (string, error) {
 	return cleaned, nil
 }
 
+// sanitizeWindowsPath validates and cleans a path in a Windows container,
+// accepting either separator and an optional drive letter. Unlike Unix paths
+// the path is kept rooted, e.g. `c:/Windows\.\System32` becomes
+// `c:\Windows\System32` and `logs/app.log` becomes `\logs\app.log`.
+func sanitizeWindowsPath(path string) (string, error) {
+	volume, rest := "", path
+	if len(path) >= 2 && path[1] == ':' {
+		if !('a' <= path[0] && path[0] <= 'z' || 'A' <= path[0] && path[0] <= 'Z') {
+			return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("invalid drive letter in %q", path)}
+		}
+		volume, rest = path[:2], path[2:]
+	}
+
+	if strings.ContainsAny(rest, `:*?"<>|`) {
+		return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("invalid character in %q", path)}
+	}
+
+	var elems []string
+	for _, elem := range strings.FieldsFunc(rest, func(r rune) bool { return r == '\\' || r == '/' }) {
+		switch elem {
+		case "..":
+			return "", &ValidationError{Field: "path", Reason: fmt.Sprintf("path traversal detected in %q", path)}
+		case ".":
+			continue
+		}
+		elems = append(elems, elem)
+	}
+
+	return volume + `\` + strings.Join(elems, `\`), nil
+}
+
+// containerParentDir returns the directory containing a path cleaned by
+// sanitizePath, as an absolute path in the container.
+func containerParentDir(sanitized string, daemonOS string) string {
+	if daemonOS != DaemonOSWindows {
+		return path.Dir("/" + sanitized)
+	}
+
+	// keep the separator after the drive letter, so the parent of `c:\x` is `c:\`
+	i := strings.LastIndex(sanitized, `\`)
+	if i == strings.Index(sanitized, `\`) {
+		return sanitized[:i+1]
+	}
+	return sanitized[:i]
+}
+
+// sanitizeTarEntryName validates the name of an entry in a tar stream from the
+// daemon, rejecting absolute names and names with ".." elements that could
+// escape the directory the archive is extracted to (zip-slip). It returns the
+// name cleaned, keeping the trailing slash of directories.
+func sanitizeTarEntryName(name string) (string, error) {
+	if strings.HasPrefix(name, "/") {
+		return "", &ValidationError{Field: "tar entry name", Reason: fmt.Sprintf("%q is absolute", name)}
+	}
+
+	if slices.Contains(strings.Split(name, "/"), "..") {
+		return "", &ValidationError{Field: "tar entry name", Reason: fmt.Sprintf("path traversal detected in %q", name)}
+	}
+
+	// sanitizePath isn't reused as it also rejects names like "backup..old"
+	cleaned := path.Clean(name)
+
+	if strings.HasSuffix(name, "/") && cleaned != "." {
+		cleaned += "/"
+	}
+
+	return cleaned, nil
+}
+
+// sanitizeTarHeader replaces the entry name, and the link target of hardlinks,
+// with their sanitizeTarEntryName form, so they can be used as map keys and
+// joined to an extraction directory. Symlinks below the top-level entry whose
+// relative target climbs out of the archive are rejected; absolute targets
+// name a path in the container and are kept, as symlinks are never created on
+// disk.
+func sanitizeTarHeader(hdr *tar.Header) error {
+	name, err := sanitizeTarEntryName(hdr.Name)
+	if err != nil {
+		return &TarError{Entry: hdr.Name, Err: err}
+	}
+	hdr.Name = name
+
+	if hdr.Typeflag == tar.TypeLink {
+		linkname, err := sanitizeTarEntryName(hdr.Linkname)
+		if err != nil {
+			return &TarError{Entry: hdr.Name, Err: fmt.Errorf("invalid hardlink target: %w", err)}
+		}
+		hdr.Linkname = linkname
+	}
+
+	// the target of the top-level entry is relative to its parent in the
+	// container, which is outside the archive
+	if hdr.Typeflag == tar.TypeSymlink && strings.Contains(strings.TrimSuffix(name, "/"), "/") && !path.IsAbs(hdr.Linkname) {
+		target := path.Join(path.Dir(strings.TrimSuffix(name, "/")), hdr.Linkname)
+		if target == ".." || strings.HasPrefix(target, "../") {
+			return &TarError{Entry: hdr.Name, Err: fmt.Errorf("symlink target %q escapes the archive", hdr.Linkname)}
+		}
+	}
+
+	return nil
+}
+
+// checkSparseHeader prepares a GNU sparse file entry to be read like a
+// regular file. The tar reader expands sparse entries to their logical
+// content, with holes read as zeros, and reports the logical size in
+// hdr.Size, so MaxFileSize applies to the expanded file. Old GNU sparse
+// entries are retyped as regular files, and PAX sparse formats the reader
+// doesn't know are rejected, as their content would be read unexpanded,
+// sparse map included.
+func checkSparseHeader(hdr *tar.Header) error {
+	if hdr.Typeflag == tar.TypeGNUSparse {
+		hdr.Typeflag = tar.TypeReg
+		return nil
+	}
+
+	major, minor := hdr.PAXRecords[paxGNUSparseMajor], hdr.PAXRecords[paxGNUSparseMinor]
+	switch {
+	case major == "" && minor == "":
+	case major == "0" && (minor == "0" || minor == "1"):
+	case major == "1" && minor == "0":
+	default:
+		return &TarError{Entry: hdr.Name, Err: fmt.Errorf("unsupported GNU sparse file format %s.%s", major, minor)}
+	}
+
+	return nil
+}
+
 // validateContainerName validates that a container name follows Docker naming conventions.
 // Docker container names must match [a-zA-Z0-9][a-zA-Z0-9_.-]* and cannot be empty.
+// A single leading slash, as in the names returned by the API, is allowed.
 func validateContainerName(name string) error {
+	// the API reports names as "/web", and accepts them back in that form
+	name = strings.TrimPrefix(name, "/")
+
 	if name == "" {
-		return fmt.Errorf("container name cannot be empty")
+		return &ValidationError{Field: "container name", Reason: "cannot be empty"}
 	}
 	
 	// Docker container name validation: must start with alphanumeric, then can contain alphanumeric, underscore, period, dash
@@ -60,16 +252,53 @@ func validateContainerName(name string) error {
 		return fmt.Errorf("failed to validate container name: %w", err)
 	}
 	if !matched {
-		return fmt.Errorf("invalid container name format (must start with letter/number, then can contain letters/numbers/underscore/period/dash): %s", name)
+		return &ValidationError{
+			Field:  "container name",
+			Reason: fmt.Sprintf("%q must start with a letter or number, then contain only letters, numbers, underscores, periods and dashes", name),
+		}
 	}
 	
 	return nil
 }
 
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// newTarReader returns a tar reader for an archive stream from the daemon,
+// transparently decompressing it when it is gzip-compressed.
+//
+// The archive endpoint behind CopyFromContainer only ever sends a plain tar,
+// and the daemon doesn't compress HTTP responses, so setting Accept-Encoding
+// (which net/http already does for tcp:// hosts) gains nothing. A gzipped
+// stream can still arrive from a compressing proxy in front of a remote
+// daemon, or from a Content-Encoding the transport didn't undo, so the
+// stream is sniffed instead of trusting headers. A tar archive can't
+// plausibly start with the gzip magic, as its first bytes are an entry name.
+// Decompression costs client CPU but nothing when the stream is plain.
+func newTarReader(r io.Reader) (*tar.Reader, error) {
+	buffered := bufio.NewReader(r)
+
+	magic, _ := buffered.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		return tar.NewReader(buffered), nil
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, &TarError{Err: fmt.Errorf("failed to decompress gzip archive: %w", err)}
+	}
+
+	return tar.NewReader(decompressed), nil
+}
+
 // extractFileFromTar extracts a single file entry from a tar reader.
 // Returns FileInfo containing the header and content, or an error if extraction fails.
-// Content will be nil for non-regular files. Files larger than MaxFileSize will be rejected.
-func extractFileFromTar(r *tar.Reader) (*FileInfo, error) {
+// Content will be nil for non-regular files, including hardlinks until they are
+// resolved by extractAllFilesFromTar. Files larger than MaxFileSize will be rejected,
+// as will entries whose names are absolute or contain ".." (see sanitizeTarHeader).
+// Unless content is TarContentFull the content of regular files is left nil;
+// with TarContentChecksum only its SHA256 is kept, and files of any size are read.
+func extractFileFromTar(r *tar.Reader, content TarContent) (*FileInfo, error) {
 	hdr, err := r.Next()
 
 	// Check if we've reached the end of the tar stream
@@ -79,48 +308,87 @@ func extractFileFromTar(r *tar.Reader) (*FileInfo, error) {
 
 	// Check for other errors
 	if err != nil {
+		return nil, &TarError{Err: err}
+	}
+
+	// Reject names that could escape the extraction root
+	if err := sanitizeTarHeader(hdr); err != nil {
+		return nil, err
+	}
+
+	if err := checkSparseHeader(hdr); err != nil {
 		return nil, err
 	}
 
-	// Check if the header is a regular file
-	if hdr.Typeflag != tar.TypeReg {
+	// Check if the header is a regular file whose content is wanted; the
+	// tar reader skips unread content when moving to the next entry
+	if hdr.Typeflag != tar.TypeReg || content == TarContentNone {
 		return &FileInfo{Header: hdr, Content: nil}, nil
 	}
 
+	// Hash the content as it is streamed, so it is never held in memory
+	if content == TarContentChecksum {
+		hash := sha256.New()
+
+		_, err = io.CopyN(hash, r, hdr.Size)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, &TarError{Entry: hdr.Name, Err: err}
+		}
+
+		return &FileInfo{Header: hdr, SHA256: hash.Sum(nil)}, nil
+	}
+
 	// Check file size before reading to prevent memory exhaustion
 	if hdr.Size > MaxFileSize {
-		return nil, fmt.Errorf("file too large: %d bytes exceeds maximum allowed size of %d bytes", hdr.Size, MaxFileSize)
+		return nil, &TarError{Entry: hdr.Name, Err: fmt.Errorf("file too large: %d bytes exceeds maximum allowed size of %d bytes", hdr.Size, MaxFileSize)}
 	}
 
-	// Read the file contents
-	var buf []byte
-	buf, err = io.ReadAll(r)
+	// Read the file contents into a buffer sized from the header, so small
+	// files are read with a single allocation. The buffer is never nil, as nil
+	// content marks non-regular files.
+	buf := bytes.NewBuffer(make([]byte, 0, min(hdr.Size, MaxReadBufferSize)))
+
+	_, err = io.CopyN(buf, r, hdr.Size)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
 
 	// Check for errors while reading the file contents
 	if err != nil {
-		return nil, err
+		return nil, &TarError{Entry: hdr.Name, Err: err}
 	}
 
 	// Return the FileInfo with header and file contents
-	return &FileInfo{Header: hdr, Content: buf}, nil
+	return &FileInfo{Header: hdr, Content: buf.Bytes()}, nil
 }
 
 // FileInfo represents metadata and content extracted from a tar archive entry.
 // It contains both the tar header information and the actual file content.
-// Content will be nil for non-regular files (directories, symlinks, etc.).
+// Content will be nil for non-regular files (directories, symlinks, etc.),
+// except for hardlinks, which share the content of the entry they link to.
 type FileInfo struct {
 	Header  *tar.Header // tar header containing file metadata
-	Content []byte      // file content, nil for non-regular files
+	Content []byte      // file content, nil for non-regular files other than resolved hardlinks
+
+	Index     int    // position of the entry in the tar stream, counting from 0
+	LocalPath string // where the entry was written by extractAllFilesToDir, empty otherwise
+	SHA256    []byte // checksum of the content written to LocalPath or hashed with TarContentChecksum, nil for non-regular files
 }
 
 // extractAllFilesFromTar extracts all files from a tar reader into a map.
-// Returns a map where keys are file names and values are FileInfo structs.
-// Files larger than MaxFileSize will be rejected with an error.
-func extractAllFilesFromTar(r *tar.Reader) (map[string]*FileInfo, error) {
+// Returns a map where keys are file names and values are FileInfo structs,
+// whose Index records the order of the entries in the stream. Hardlinks are resolved to the content of their target entry.
+// Files larger than MaxFileSize will be rejected with an error unless content
+// is TarContentChecksum, which keeps only checksums, or TarContentNone, which
+// keeps only the headers.
+func extractAllFilesFromTar(r *tar.Reader, content TarContent) (map[string]*FileInfo, error) {
 	files := make(map[string]*FileInfo)
 
-	for {
-		fileInfo, err := extractFileFromTar(r)
+	for index := 0; ; index++ {
+		fileInfo, err := extractFileFromTar(r, content)
 		if err == io.EOF {
 			break
 		}
@@ -128,8 +396,250 @@ func extractAllFilesFromTar(r *tar.Reader) (map[string]*FileInfo, error) {
 			return nil, err
 		}
 
+		fileInfo.Index = index
 		files[fileInfo.Header.Name] = fileInfo
 	}
 
+	resolveHardlinks(files)
+
 	return files, nil
 }
+
+// resolveHardlinks copies the content, or checksum, of each hardlink's target
+// into the hardlink entry. This runs as a second pass once the whole archive has been
+// read, so targets appearing later in the stream are still found. Chains of
+// hardlinks are followed; links whose target is not in the archive keep nil content.
+func resolveHardlinks(files map[string]*FileInfo) {
+	for _, fileInfo := range files {
+		if fileInfo.Header.Typeflag != tar.TypeLink {
+			continue
+		}
+
+		target := files[fileInfo.Header.Linkname]
+		for seen := 0; target != nil && target.Header.Typeflag == tar.TypeLink && seen < len(files); seen++ {
+			target = files[target.Header.Linkname]
+		}
+
+		if target != nil && target.Content != nil {
+			fileInfo.Content = target.Content
+		}
+		if target != nil && target.SHA256 != nil {
+			fileInfo.SHA256 = target.SHA256
+		}
+	}
+}
+
+// extractFileToDir extracts a single entry from a tar reader under dir,
+// streaming the content of regular files to disk instead of holding it in
+// memory, so its size is not limited by MaxFileSize. The returned FileInfo has
+// nil content and records where the entry was written and the checksum of its
+// content. Entry names are checked with sanitizeTarHeader so crafted names
+// can't escape dir. Only regular files and directories are written; other entries
+// are returned with just their header.
+func extractFileToDir(r *tar.Reader, dir string) (*FileInfo, error) {
+	hdr, err := r.Next()
+
+	// Check if we've reached the end of the tar stream
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	// Check for other errors
+	if err != nil {
+		return nil, &TarError{Err: err}
+	}
+
+	if err := sanitizeTarHeader(hdr); err != nil {
+		return nil, err
+	}
+
+	if err := checkSparseHeader(hdr); err != nil {
+		return nil, err
+	}
+
+	localPath := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+	perm := os.FileMode(hdr.Mode).Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		// directories stay writable by the owner so their entries can be written
+		if err := os.MkdirAll(localPath, perm|0o700); err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(localPath, perm|0o700); err != nil {
+			return nil, err
+		}
+		return &FileInfo{Header: hdr, LocalPath: localPath}, nil
+
+	case tar.TypeReg:
+
+	default:
+		return &FileInfo{Header: hdr}, nil
+	}
+
+	// archives of explicit paths don't include their parent directories
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), r); err != nil {
+		return nil, &TarError{Entry: hdr.Name, Err: err}
+	}
+
+	// the mode passed to OpenFile is reduced by the umask and ignored for existing files
+	if err := file.Chmod(perm); err != nil {
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return &FileInfo{Header: hdr, LocalPath: localPath, SHA256: hash.Sum(nil)}, nil
+}
+
+// extractAllFilesToDir extracts all entries from a tar reader under dir with
+// extractFileToDir, returning their metadata keyed by entry name, with Index
+// recording the order of the entries in the stream. Hardlinks
+// are recreated on disk once their target has been written.
+func extractAllFilesToDir(r *tar.Reader, dir string) (map[string]*FileInfo, error) {
+	files := make(map[string]*FileInfo)
+
+	for index := 0; ; index++ {
+		fileInfo, err := extractFileToDir(r, dir)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fileInfo.Index = index
+		files[fileInfo.Header.Name] = fileInfo
+	}
+
+	if err := linkHardlinksInDir(files, dir); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// linkHardlinksInDir recreates each hardlink entry as a hard link to the file
+// written for its target, following chains like resolveHardlinks. Links whose
+// target was not written are left off disk.
+func linkHardlinksInDir(files map[string]*FileInfo, dir string) error {
+	for _, fileInfo := range files {
+		if fileInfo.Header.Typeflag != tar.TypeLink {
+			continue
+		}
+
+		target := files[fileInfo.Header.Linkname]
+		for seen := 0; target != nil && target.Header.Typeflag == tar.TypeLink && seen < len(files); seen++ {
+			target = files[target.Header.Linkname]
+		}
+
+		if target == nil || target.SHA256 == nil {
+			continue
+		}
+
+		localPath := filepath.Join(dir, filepath.FromSlash(fileInfo.Header.Name))
+		if err := os.Remove(localPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err := os.Link(target.LocalPath, localPath); err != nil {
+			return err
+		}
+
+		fileInfo.LocalPath = localPath
+		fileInfo.SHA256 = target.SHA256
+	}
+
+	return nil
+}
+
+// extractXattrs returns the extended attributes (e.g. security.selinux) stored
+// in the PAX records of a tar header, keyed by attribute name without the
+// SCHILY.xattr. prefix.
+func extractXattrs(hdr *tar.Header) map[string]string {
+	xattrs := make(map[string]string)
+
+	for key, value := range hdr.PAXRecords {
+		if name, found := strings.CutPrefix(key, paxXattrPrefix); found {
+			xattrs[name] = value
+		}
+	}
+
+	return xattrs
+}
+
+// createTarFromDirectory writes the contents of dir to w as a tar archive, with
+// entry names relative to dir. Regular files, directories and symlinks are
+// included; other file types (sockets, devices) are skipped.
+func createTarFromDirectory(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case !info.Mode().IsRegular() && !info.IsDir():
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,476 @@
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry describes an entry written by buildTar.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+// buildTar returns a tar stream holding entries, written without the checks a
+// well-behaved archiver would apply, like the daemon's response could be.
+func buildTar(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, entry := range entries {
+		typeflag := entry.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+
+		hdr := &tar.Header{
+			Name:     entry.name,
+			Typeflag: typeflag,
+			Linkname: entry.linkname,
+			Mode:     0o644,
+			Size:     int64(len(entry.content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("failed to write header for %q: %v", entry.name, err)
+		}
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			t.Fatalf("failed to write content for %q: %v", entry.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestExtractRejectsEscapingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{
+			name:    "parent traversal",
+			entries: []tarEntry{{name: "../../etc/passwd", content: "root:x:0:0"}},
+		},
+		{
+			name:    "nested parent traversal",
+			entries: []tarEntry{{name: "dir/../../passwd", content: "root:x:0:0"}},
+		},
+		{
+			name:    "absolute name",
+			entries: []tarEntry{{name: "/etc/passwd", content: "root:x:0:0"}},
+		},
+		{
+			name: "hardlink to parent",
+			entries: []tarEntry{
+				{name: "dir/", typeflag: tar.TypeDir},
+				{name: "dir/passwd", typeflag: tar.TypeLink, linkname: "../../etc/passwd"},
+			},
+		},
+		{
+			name: "hardlink to absolute path",
+			entries: []tarEntry{
+				{name: "dir/", typeflag: tar.TypeDir},
+				{name: "dir/passwd", typeflag: tar.TypeLink, linkname: "/etc/passwd"},
+			},
+		},
+		{
+			name: "symlink escaping the archive",
+			entries: []tarEntry{
+				{name: "dir/", typeflag: tar.TypeDir},
+				{name: "dir/etc", typeflag: tar.TypeSymlink, linkname: "../../etc"},
+				{name: "dir/etc/passwd", content: "root:x:0:0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := buildTar(t, tt.entries...)
+
+			_, err := extractAllFilesFromTar(tar.NewReader(bytes.NewReader(stream)), TarContentFull)
+			var tarErr *TarError
+			if !errors.As(err, &tarErr) {
+				t.Errorf("extractAllFilesFromTar: expected a TarError, got: %v", err)
+			}
+
+			root := t.TempDir()
+			dir := filepath.Join(root, "out")
+
+			_, err = extractAllFilesToDir(tar.NewReader(bytes.NewReader(stream)), dir)
+			if !errors.As(err, &tarErr) {
+				t.Errorf("extractAllFilesToDir: expected a TarError, got: %v", err)
+			}
+
+			for _, escaped := range []string{filepath.Join(root, "passwd"), filepath.Join(root, "etc", "passwd")} {
+				if _, err := os.Stat(escaped); err == nil {
+					t.Errorf("%s was written outside the extraction directory", escaped)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractKeepsContainedLinks(t *testing.T) {
+	stream := buildTar(t,
+		tarEntry{name: "link", typeflag: tar.TypeSymlink, linkname: "../lib/target"},
+		tarEntry{name: "dir/", typeflag: tar.TypeDir},
+		tarEntry{name: "dir/file", content: "content"},
+		tarEntry{name: "dir/absolute", typeflag: tar.TypeSymlink, linkname: "/usr/share/zoneinfo/UTC"},
+		tarEntry{name: "dir/sibling", typeflag: tar.TypeSymlink, linkname: "../dir/file"},
+		tarEntry{name: "dir/hardlink", typeflag: tar.TypeLink, linkname: "./dir/file"},
+	)
+
+	files, err := extractAllFilesFromTar(tar.NewReader(bytes.NewReader(stream)), TarContentFull)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := files["dir/hardlink"]; got == nil || got.Header.Linkname != "dir/file" || string(got.Content) != "content" {
+		t.Errorf("expected the hardlink to resolve to dir/file, got %+v", got)
+	}
+	if got := files["link"]; got == nil || got.Header.Linkname != "../lib/target" {
+		t.Errorf("expected the top-level symlink target to be kept, got %+v", got)
+	}
+}
+
+func TestExtractFileFromTarContent(t *testing.T) {
+	stream := buildTar(t, tarEntry{name: "file", content: "content"})
+
+	tests := []struct {
+		content    TarContent
+		wantBytes  bool
+		wantSHA256 bool
+	}{
+		{content: TarContentFull, wantBytes: true},
+		{content: TarContentChecksum, wantSHA256: true},
+		{content: TarContentNone},
+	}
+
+	for _, tt := range tests {
+		fileInfo, err := extractFileFromTar(tar.NewReader(bytes.NewReader(stream)), tt.content)
+		if err != nil {
+			t.Fatalf("content %d: unexpected error: %v", tt.content, err)
+		}
+		if (fileInfo.Content != nil) != tt.wantBytes {
+			t.Errorf("content %d: expected content %t, got %q", tt.content, tt.wantBytes, fileInfo.Content)
+		}
+		if (fileInfo.SHA256 != nil) != tt.wantSHA256 {
+			t.Errorf("content %d: expected checksum %t, got %x", tt.content, tt.wantSHA256, fileInfo.SHA256)
+		}
+	}
+
+	_, err := extractFileFromTar(tar.NewReader(bytes.NewReader(buildTar(t))), TarContentFull)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF for an empty archive, got: %v", err)
+	}
+}
+
+// setTarChecksum recomputes the checksum of the header block at the start of
+// block after its fields were patched.
+func setTarChecksum(block []byte) {
+	copy(block[148:156], "        ")
+
+	sum := 0
+	for _, b := range block[:512] {
+		sum += int(b)
+	}
+	copy(block[148:156], fmt.Sprintf("%06o\x00 ", sum))
+}
+
+// oldGNUSparseTar returns an archive holding file as an old GNU sparse entry
+// of size bytes, whose only data is content at offset.
+func oldGNUSparseTar(t *testing.T, size, offset int64, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	hdr := &tar.Header{Name: "file", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content)), Format: tar.FormatGNU}
+	if err := tw.WriteHeader(hdr); err != nil {
+		t.Fatalf("failed to write header: %v", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to write content: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	stream := buf.Bytes()
+	stream[156] = tar.TypeGNUSparse
+	copy(stream[386:398], fmt.Sprintf("%011o\x00", offset))
+	copy(stream[398:410], fmt.Sprintf("%011o\x00", len(content)))
+	stream[482] = 0
+	copy(stream[483:495], fmt.Sprintf("%011o\x00", size))
+	setTarChecksum(stream)
+
+	return stream
+}
+
+// paxSparseTar returns an archive holding file with the PAX records of a GNU
+// sparse format of the given version.
+func paxSparseTar(t *testing.T, major, minor string) []byte {
+	t.Helper()
+
+	var records string
+	for _, record := range []string{paxGNUSparseMajor + "=" + major, paxGNUSparseMinor + "=" + minor} {
+		// the length prefix counts itself
+		n := len(record) + 3
+		for len(fmt.Sprintf("%d %s\n", n, record)) != n {
+			n++
+		}
+		records += fmt.Sprintf("%d %s\n", n, record)
+	}
+
+	stream := buildTar(t,
+		tarEntry{name: "PaxHeaders/file", content: records},
+		tarEntry{name: "file", content: "content"},
+	)
+	stream[156] = tar.TypeXHeader
+	setTarChecksum(stream)
+
+	return stream
+}
+
+func TestExtractOldGNUSparse(t *testing.T) {
+	stream := oldGNUSparseTar(t, 10, 4, "ab")
+
+	fileInfo, err := extractFileFromTar(tar.NewReader(bytes.NewReader(stream)), TarContentFull)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fileInfo.Header.Typeflag != tar.TypeReg {
+		t.Errorf("expected a regular file, got typeflag %q", fileInfo.Header.Typeflag)
+	}
+	if fileInfo.Header.Size != 10 {
+		t.Errorf("expected the logical size 10, got %d", fileInfo.Header.Size)
+	}
+	if want := "\x00\x00\x00\x00ab\x00\x00\x00\x00"; string(fileInfo.Content) != want {
+		t.Errorf("expected holes read as zeros %q, got %q", want, fileInfo.Content)
+	}
+}
+
+func TestExtractPAXSparseFormats(t *testing.T) {
+	tests := []struct {
+		major, minor string
+		wantErr      bool
+	}{
+		{major: "0", minor: "0"},
+		{major: "0", minor: "1"},
+		{major: "1", minor: "0"},
+		{major: "2", minor: "0", wantErr: true},
+		{major: "1", minor: "5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		hdr := &tar.Header{
+			Name:       "file",
+			Typeflag:   tar.TypeReg,
+			PAXRecords: map[string]string{paxGNUSparseMajor: tt.major, paxGNUSparseMinor: tt.minor},
+		}
+
+		err := checkSparseHeader(hdr)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("format %s.%s: expected error %t, got: %v", tt.major, tt.minor, tt.wantErr, err)
+		}
+	}
+
+	_, err := extractFileFromTar(tar.NewReader(bytes.NewReader(paxSparseTar(t, "2", "0"))), TarContentFull)
+	var tarErr *TarError
+	if !errors.As(err, &tarErr) || !strings.Contains(err.Error(), "unsupported GNU sparse file format 2.0") {
+		t.Fatalf("expected a TarError for an unknown sparse format, got: %v", err)
+	}
+}
+
+func TestSanitizePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		daemonOS string
+		want     string
+		wantErr  bool
+	}{
+		{path: "/etc/hosts", daemonOS: "linux", want: "etc/hosts"},
+		{path: "etc/hosts", daemonOS: "linux", want: "etc/hosts"},
+		{path: "/var//log/./app.log", daemonOS: "linux", want: "var/log/app.log"},
+		{path: "/srv/app/", daemonOS: "linux", want: "srv/app"},
+		{path: "/", daemonOS: "linux", want: ""},
+		{path: "/etc/hosts", daemonOS: "", want: "etc/hosts"},
+		{path: "", daemonOS: "linux", wantErr: true},
+		{path: "../etc/passwd", daemonOS: "linux", wantErr: true},
+		{path: "/srv/../../etc/passwd", daemonOS: "linux", want: "etc/passwd"},
+		{path: "srv/../../etc/passwd", daemonOS: "linux", wantErr: true},
+		{path: `c:/Windows\.\System32`, daemonOS: DaemonOSWindows, want: `c:\Windows\System32`},
+		{path: `C:\inetpub\logs\`, daemonOS: DaemonOSWindows, want: `C:\inetpub\logs`},
+		{path: `C:\`, daemonOS: DaemonOSWindows, want: `C:\`},
+		{path: "logs/app.log", daemonOS: DaemonOSWindows, want: `\logs\app.log`},
+		{path: `\app\config.json`, daemonOS: DaemonOSWindows, want: `\app\config.json`},
+		{path: "", daemonOS: DaemonOSWindows, wantErr: true},
+		{path: `1:\app`, daemonOS: DaemonOSWindows, wantErr: true},
+		{path: `c:\app\*.log`, daemonOS: DaemonOSWindows, wantErr: true},
+		{path: `c:\app\file:stream`, daemonOS: DaemonOSWindows, wantErr: true},
+		{path: `c:\app\..\Windows`, daemonOS: DaemonOSWindows, wantErr: true},
+		{path: `..\Windows`, daemonOS: DaemonOSWindows, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := sanitizePath(tt.path, tt.daemonOS)
+		if tt.wantErr {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("sanitizePath(%q, %q): expected a ValidationError, got %q, %v", tt.path, tt.daemonOS, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("sanitizePath(%q, %q): unexpected error: %v", tt.path, tt.daemonOS, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sanitizePath(%q, %q) = %q, want %q", tt.path, tt.daemonOS, got, tt.want)
+		}
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	err := errors.New("permission denied")
+
+	tests := []struct {
+		name      string
+		container string
+		details   string
+		err       error
+		want      string
+	}{
+		{
+			name:      "container and error",
+			container: "web",
+			err:       err,
+			want:      `Failed to read file "/etc/hosts" in container "web": permission denied`,
+		},
+		{
+			name:      "container, details and error",
+			container: "web",
+			details:   "the file is not readable",
+			err:       err,
+			want:      `Failed to read file "/etc/hosts" in container "web": the file is not readable (permission denied)`,
+		},
+		{
+			name:      "container and details",
+			container: "web",
+			details:   "it is a directory",
+			want:      `Failed to read file "/etc/hosts" in container "web": it is a directory`,
+		},
+		{
+			name: "error only",
+			err:  err,
+			want: `Failed to read file "/etc/hosts": permission denied`,
+		},
+		{
+			name:    "details and error",
+			details: "the file is not readable",
+			err:     err,
+			want:    `Failed to read file "/etc/hosts": the file is not readable (permission denied)`,
+		},
+		{
+			name:    "details only",
+			details: "it is a directory",
+			want:    `Failed to read file "/etc/hosts": it is a directory`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatError("read", `file "/etc/hosts"`, tt.container, tt.details, tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkExtractSmallFiles compares reading the content of many small files
+// into buffers sized from their headers, as extractFileFromTar does, against
+// growing them with io.ReadAll.
+func BenchmarkExtractSmallFiles(b *testing.B) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := bytes.Repeat([]byte("x"), 4096)
+	for i := range 1000 {
+		if err := tw.WriteHeader(&tar.Header{Name: fmt.Sprintf("dir/file%d", i), Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		b.Fatal(err)
+	}
+	stream := buf.Bytes()
+
+	b.Run("presized", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := extractAllFilesFromTar(tar.NewReader(bytes.NewReader(stream)), TarContentFull); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("readall", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			r := tar.NewReader(bytes.NewReader(stream))
+			for {
+				if _, err := r.Next(); err == io.EOF {
+					break
+				} else if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := io.ReadAll(r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestValidateContainerName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "web"},
+		{name: "/web"},
+		{name: "my-app_1.2"},
+		{name: "0123456789ab"},
+		{name: "", wantErr: true},
+		{name: "/", wantErr: true},
+		{name: "//web", wantErr: true},
+		{name: "-web", wantErr: true},
+		{name: ".web", wantErr: true},
+		{name: "web/app", wantErr: true},
+		{name: "web app", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := validateContainerName(tt.name)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("validateContainerName(%q): expected error %t, got: %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
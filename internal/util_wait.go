@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"context"
+	"time"
+)
+
+// Wait backoff bounds
+const (
+	// WaitInitialInterval is the delay before the first re-check of a wait condition
+	WaitInitialInterval = 500 * time.Millisecond
+	// WaitMaxInterval caps the doubling delay between re-checks
+	WaitMaxInterval = 5 * time.Second
+)
+
+// PollUntil calls condition until it reports done, waiting interval after the
+// first call and doubling the wait after each one up to maxInterval. It
+// returns condition's error as soon as there is one, the context's error once
+// ctx is done, and a WaitTimeoutError once timeout has passed without
+// condition being done. condition is always called at least once, and once
+// more at the timeout.
+func PollUntil(ctx context.Context, interval, maxInterval, timeout time.Duration, condition func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return &WaitTimeoutError{Timeout: timeout}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(interval, remaining)):
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
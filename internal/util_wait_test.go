@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPollUntilSucceeds(t *testing.T) {
+	calls := 0
+	err := PollUntil(context.Background(), time.Millisecond, time.Millisecond, time.Second, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestPollUntilTimesOut(t *testing.T) {
+	calls := 0
+	err := PollUntil(context.Background(), time.Millisecond, 2*time.Millisecond, 20*time.Millisecond, func() (bool, error) {
+		calls++
+		return false, nil
+	})
+
+	var timedOut *WaitTimeoutError
+	if !errors.As(err, &timedOut) {
+		t.Fatalf("expected a WaitTimeoutError, got: %v", err)
+	}
+	if timedOut.Timeout != 20*time.Millisecond {
+		t.Errorf("expected timeout 20ms, got %s", timedOut.Timeout)
+	}
+	if calls < 2 {
+		t.Errorf("expected the condition to be re-checked at the timeout, got %d calls", calls)
+	}
+}
+
+func TestPollUntilAbortsOnError(t *testing.T) {
+	abort := errors.New("abort")
+	calls := 0
+	err := PollUntil(context.Background(), time.Millisecond, time.Millisecond, time.Second, func() (bool, error) {
+		calls++
+		if calls == 2 {
+			return false, abort
+		}
+		return false, nil
+	})
+	if !errors.Is(err, abort) {
+		t.Fatalf("expected the condition's error, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestPollUntilStopsOnContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := PollUntil(ctx, time.Second, time.Second, time.Minute, func() (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
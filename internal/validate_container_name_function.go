@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+type ValidateContainerNameFunction struct{}
+
+func NewValidateContainerNameFunction() function.Function {
+	return &ValidateContainerNameFunction{}
+}
+
+func (f *ValidateContainerNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_container_name"
+}
+
+func (f *ValidateContainerNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validate a container name",
+		MarkdownDescription: `
+			Checks a container name against the same rules the provider applies
+			before talking to the daemon, returning true when the name is valid.
+
+			Fails with the provider's validation error when the name is invalid,
+			so it can be wrapped in can() inside variable validation blocks.
+		`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The container name to validate",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidateContainerNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &name))
+
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateContainerName(name); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, true))
+}